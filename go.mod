@@ -3,6 +3,9 @@ module github.com/terraform-providers/terraform-provider-statuscake
 go 1.14
 
 require (
-	github.com/DreamItGetIT/statuscake v0.0.0-20190809134845-9d26ad75405b
+	github.com/google/go-querystring v1.0.0
 	github.com/hashicorp/terraform v0.12.0
+	github.com/zclconf/go-cty v0.0.0-20190516203816-4fecf87372ec
+	github.com/zclconf/go-cty v0.0.0-20190516203816-4fecf87372ec
+	go.opencensus.io v0.18.0
 )
@@ -0,0 +1,269 @@
+// Package otlp exports OpenCensus spans to an OpenTelemetry collector, or to
+// anything else that accepts OTLP traces over HTTP with JSON encoding, such
+// as Jaeger.
+//
+// Spans are buffered as they end and sent by Flush, or in the background by
+// FlushAsync.
+package otlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+// maxBuffered is how many spans are kept between flushes. Older spans are
+// dropped beyond it, so a collector that is down cannot use up memory.
+const maxBuffered = 4096
+
+// Exporter is a trace.Exporter sending spans to an OTLP/HTTP endpoint.
+type Exporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+
+	mu       sync.Mutex
+	spans    []*trace.SpanData
+	dropped  int
+	flushing bool
+	again    bool
+}
+
+// NewExporter returns an exporter posting to endpoint, the full URL of the
+// traces path such as http://localhost:4318/v1/traces.
+func NewExporter(endpoint, serviceName string, headers map[string]string) *Exporter {
+	return &Exporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ExportSpan buffers a finished span until the next Flush.
+func (e *Exporter) ExportSpan(sd *trace.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.spans) >= maxBuffered {
+		e.spans = e.spans[1:]
+		e.dropped++
+	}
+	e.spans = append(e.spans, sd)
+}
+
+// Flush sends every buffered span in one request.
+func (e *Exporter) Flush() error {
+	e.mu.Lock()
+	spans, dropped := e.spans, e.dropped
+	e.spans, e.dropped = nil, 0
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("exporting %d spans: %s", len(spans), err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("exporting %d spans: %s", len(spans), resp.Status)
+	}
+	if dropped > 0 {
+		return fmt.Errorf("dropped %d spans while the buffer was full", dropped)
+	}
+
+	return nil
+}
+
+// FlushAsync sends the buffered spans in the background and returns at once.
+// Flushes run one at a time: spans ending while one is in progress are sent
+// by another right after it, so a collector that is down holds up a single
+// request rather than the caller. onError, if set, is called with the error
+// of every failed flush.
+func (e *Exporter) FlushAsync(onError func(error)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.flushing {
+		e.again = true
+		return
+	}
+	e.flushing = true
+
+	go func() {
+		for {
+			if err := e.Flush(); err != nil && onError != nil {
+				onError(err)
+			}
+
+			e.mu.Lock()
+			if !e.again {
+				e.flushing = false
+				e.mu.Unlock()
+				return
+			}
+			e.again = false
+			e.mu.Unlock()
+		}
+	}()
+}
+
+// The types below are the subset of the OTLP/JSON trace request used here.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLP span kinds and status codes
+const (
+	kindInternal = 1
+	kindServer   = 2
+	kindClient   = 3
+
+	statusOK    = 1
+	statusError = 2
+)
+
+func (e *Exporter) request(spans []*trace.SpanData) exportRequest {
+	out := make([]span, len(spans))
+	for i, sd := range spans {
+		out[i] = convertSpan(sd)
+	}
+
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: []keyValue{stringKeyValue("service.name", e.serviceName)}},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: e.serviceName},
+				Spans: out,
+			}},
+		}},
+	}
+}
+
+func convertSpan(sd *trace.SpanData) span {
+	s := span{
+		TraceID:           hex.EncodeToString(sd.TraceID[:]),
+		SpanID:            hex.EncodeToString(sd.SpanID[:]),
+		Name:              sd.Name,
+		Kind:              kindInternal,
+		StartTimeUnixNano: strconv.FormatInt(sd.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(sd.EndTime.UnixNano(), 10),
+		Status:            status{Code: statusOK},
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		s.ParentSpanID = hex.EncodeToString(sd.ParentSpanID[:])
+	}
+	switch sd.SpanKind {
+	case trace.SpanKindServer:
+		s.Kind = kindServer
+	case trace.SpanKindClient:
+		s.Kind = kindClient
+	}
+	if sd.Code != trace.StatusCodeOK {
+		s.Status = status{Code: statusError, Message: sd.Message}
+	}
+
+	keys := make([]string, 0, len(sd.Attributes))
+	for k := range sd.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s.Attributes = append(s.Attributes, attributeKeyValue(k, sd.Attributes[k]))
+	}
+
+	return s
+}
+
+func attributeKeyValue(key string, value interface{}) keyValue {
+	switch v := value.(type) {
+	case bool:
+		return keyValue{Key: key, Value: anyValue{BoolValue: &v}}
+	case int64:
+		i := strconv.FormatInt(v, 10)
+		return keyValue{Key: key, Value: anyValue{IntValue: &i}}
+	}
+
+	return stringKeyValue(key, fmt.Sprint(value))
+}
+
+func stringKeyValue(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: &value}}
+}
@@ -0,0 +1,145 @@
+package otlp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+func TestExporter_Flush(t *testing.T) {
+	var got exportRequest
+	var auth string
+	var requests int
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		auth = r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid request body %s: %s", body, err)
+		}
+	}))
+	defer collector.Close()
+
+	e := NewExporter(collector.URL+"/v1/traces", "svc", map[string]string{"Authorization": "Bearer token"})
+	start := time.Unix(1, 0)
+	e.ExportSpan(&trace.SpanData{
+		SpanContext: trace.SpanContext{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{2},
+		},
+		ParentSpanID: trace.SpanID{3},
+		SpanKind:     trace.SpanKindClient,
+		Name:         "StatusCake GET /Tests",
+		StartTime:    start,
+		EndTime:      start.Add(time.Second),
+		Attributes:   map[string]interface{}{"http.status_code": int64(500), "http.method": "GET"},
+		Status:       trace.Status{Code: trace.StatusCodeUnknown, Message: "boom"},
+	})
+
+	if err := e.Flush(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected one request, as the second flush has nothing to send, got %d", requests)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("expected the configured headers to be sent, got Authorization %q", auth)
+	}
+
+	if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	if v := *got.ResourceSpans[0].Resource.Attributes[0].Value.StringValue; v != "svc" {
+		t.Errorf("expected service.name svc, got %q", v)
+	}
+
+	s := got.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if s.TraceID != "01000000000000000000000000000000" || s.SpanID != "0200000000000000" || s.ParentSpanID != "0300000000000000" {
+		t.Errorf("unexpected IDs: %+v", s)
+	}
+	if s.Kind != kindClient {
+		t.Errorf("expected kind %d, got %d", kindClient, s.Kind)
+	}
+	if s.StartTimeUnixNano != "1000000000" || s.EndTimeUnixNano != "2000000000" {
+		t.Errorf("unexpected times: %s to %s", s.StartTimeUnixNano, s.EndTimeUnixNano)
+	}
+	if s.Status.Code != statusError || s.Status.Message != "boom" {
+		t.Errorf("unexpected status: %+v", s.Status)
+	}
+	if len(s.Attributes) != 2 || s.Attributes[0].Key != "http.method" || *s.Attributes[1].Value.IntValue != "500" {
+		t.Errorf("unexpected attributes: %+v", s.Attributes)
+	}
+}
+
+func TestExporter_FlushFailure(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	e := NewExporter(collector.URL, "svc", nil)
+	e.ExportSpan(&trace.SpanData{Name: "span"})
+
+	if err := e.Flush(); err == nil {
+		t.Fatal("expected an error from a failing collector")
+	}
+}
+
+func TestExporter_FlushAsync(t *testing.T) {
+	arrived := make(chan struct{}, 4)
+	release := make(chan struct{})
+	received := make(chan int, 4)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-release
+		var req exportRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received <- len(req.ResourceSpans[0].ScopeSpans[0].Spans)
+	}))
+	defer collector.Close()
+
+	e := NewExporter(collector.URL, "svc", nil)
+	e.ExportSpan(&trace.SpanData{Name: "first"})
+
+	// Neither call waits for the collector, which is not answering yet
+	start := time.Now()
+	e.FlushAsync(nil)
+	<-arrived
+	e.ExportSpan(&trace.SpanData{Name: "second"})
+	e.ExportSpan(&trace.SpanData{Name: "third"})
+	e.FlushAsync(nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected FlushAsync to return at once, took %s", elapsed)
+	}
+	close(release)
+
+	for _, want := range []int{1, 2} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Errorf("expected %d spans in the request, got %d", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected a request with %d spans", want)
+		}
+	}
+}
+
+func TestExporter_dropsOldestSpans(t *testing.T) {
+	e := NewExporter("http://localhost", "svc", nil)
+	for i := 0; i < maxBuffered+2; i++ {
+		e.ExportSpan(&trace.SpanData{Name: "span"})
+	}
+
+	if len(e.spans) != maxBuffered || e.dropped != 2 {
+		t.Errorf("expected %d buffered and 2 dropped spans, got %d and %d", maxBuffered, len(e.spans), e.dropped)
+	}
+}
@@ -0,0 +1,49 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Account represents the StatusCake account the credentials belong to
+type Account struct {
+	Username  string `json:"Username"`
+	FirstName string `json:"FirstName"`
+	LastName  string `json:"LastName"`
+	Email     string `json:"Email"`
+	Plan      string `json:"Plan"`
+
+	// MaxTests is how many tests the plan allows, 0 when the API does not
+	// report it
+	MaxTests int `json:"MaxTests"`
+}
+
+type accountResponse struct {
+	Success bool     `json:"Success"`
+	Message string   `json:"Message"`
+	Details *Account `json:"Details"`
+}
+
+// Account checks the credentials and returns the account they belong to.
+// Rejected credentials return an AuthenticationError.
+func (c *Client) Account() (*Account, error) {
+	if c.apiVersion == APIVersionV1 {
+		return c.v1Account()
+	}
+
+	resp, err := c.get("/Auth", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting StatusCake account: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var ar accountResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return nil, fmt.Errorf("Error decoding StatusCake account: %w", err)
+	}
+	if !ar.Success || ar.Details == nil {
+		return nil, &AuthenticationError{Message: ar.Message}
+	}
+
+	return ar.Details, nil
+}
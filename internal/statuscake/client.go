@@ -0,0 +1,415 @@
+package statuscake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Versions of the StatusCake API a Client can speak
+const (
+	// APIVersionLegacy is the original API, authenticated with a username
+	// and API key
+	APIVersionLegacy = "legacy"
+
+	// APIVersionV1 is the v1 REST API, authenticated with a bearer token
+	APIVersionV1 = "v1"
+)
+
+const (
+	apiBaseURL   = "https://app.statuscake.com/API"
+	apiV1BaseURL = "https://api.statuscake.com/v1"
+)
+
+type responseBody struct {
+	io.Reader
+}
+
+func (r *responseBody) Close() error {
+	return nil
+}
+
+// Auth wraps the authorisation headers required for each request
+type Auth struct {
+	Username string
+	Apikey   string
+
+	// Token is a bearer token sent instead of Username and Apikey
+	Token string
+}
+
+func (a *Auth) validate() error {
+	if a.Token != "" {
+		return nil
+	}
+
+	e := make(ValidationError)
+
+	if a.Username == "" {
+		e["Username"] = "is required"
+	}
+
+	if a.Apikey == "" {
+		e["Apikey"] = "is required"
+	}
+
+	if len(e) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type apiClient interface {
+	get(string, url.Values) (*http.Response, error)
+	delete(string, url.Values) (*http.Response, error)
+	put(string, url.Values) (*http.Response, error)
+}
+
+// API is what callers need from a StatusCake backend. Client implements it
+// over either API version, returning the same types and errors from both.
+type API interface {
+	Tests() Tests
+	ContactGroups() ContactGroups
+	Locations() Locations
+	Account() (*Account, error)
+}
+
+var _ API = (*Client)(nil)
+
+// Client is the http client that wraps the remote API. It is safe for
+// concurrent use once created.
+type Client struct {
+	c        httpClient
+	baseURL  string
+	auth     Auth
+	readOnly bool
+
+	// apiVersion is APIVersionLegacy or APIVersionV1
+	apiVersion string
+
+	// credentials, when set, supplies the auth of every request instead
+	credentials func() (Auth, error)
+
+	// userAgent replaces Go's default User-Agent header when set
+	userAgent string
+
+	// wireLogging logs every request and response, see WithWireLogging
+	wireLogging bool
+
+	// ctx is the context every request runs under, and requestTimeout the
+	// deadline of a single request. A zero requestTimeout means no deadline.
+	ctx            context.Context
+	requestTimeout time.Duration
+
+	testsOnce   sync.Once
+	testsClient Tests
+}
+
+// Option configures optional behaviour of a Client
+type Option func(*Client)
+
+// WithReadOnly makes the client refuse every PUT and DELETE request
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Client) {
+		c.readOnly = readOnly
+	}
+}
+
+// WithCredentials fetches the auth of every request from credentials, for
+// credentials that expire. credentials is called concurrently and should
+// cache what it returns.
+func WithCredentials(credentials func() (Auth, error)) Option {
+	return func(c *Client) {
+		c.credentials = credentials
+	}
+}
+
+// WithUserAgent sends userAgent as the User-Agent header of every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithAPIVersion selects the API version the client speaks, and its base URL
+// unless WithBaseURL sets one
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithBaseURL sends requests to baseURL instead of the StatusCake API, such
+// as a local stand-in server in tests
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithContext makes every request run under ctx, so cancelling ctx aborts
+// the request in flight and fails every later one
+func WithContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.ctx = ctx
+	}
+}
+
+// WithRequestTimeout fails a request that takes longer than timeout. Zero
+// means no limit.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// New returns a new Client
+func New(auth Auth, opts ...Option) (*Client, error) {
+	if err := auth.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		c:          &http.Client{},
+		ctx:        context.Background(),
+		auth:       auth,
+		apiVersion: APIVersionLegacy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	switch c.apiVersion {
+	case APIVersionLegacy:
+		if c.baseURL == "" {
+			c.baseURL = apiBaseURL
+		}
+	case APIVersionV1:
+		if c.baseURL == "" {
+			c.baseURL = apiV1BaseURL
+		}
+	default:
+		return nil, fmt.Errorf("unknown StatusCake API version %q", c.apiVersion)
+	}
+
+	return c, nil
+}
+
+func (c *Client) newRequest(method string, path string, v url.Values, body io.Reader) (*http.Request, error) {
+	url := fmt.Sprintf("%s%s", c.baseURL, path)
+	if v != nil {
+		url = fmt.Sprintf("%s?%s", url, v.Encode())
+	}
+
+	r, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := c.auth
+	if c.credentials != nil {
+		if auth, err = c.credentials(); err != nil {
+			return nil, err
+		}
+	}
+	if auth.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+auth.Token)
+	} else {
+		r.Header.Set("Username", auth.Username)
+		r.Header.Set("API", auth.Apikey)
+	}
+	if c.userAgent != "" {
+		r.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return r, nil
+}
+
+func (c *Client) doRequest(r *http.Request) (*http.Response, error) {
+	ctx, span := c.startSpan(r)
+	resp, err := c.send(ctx, r)
+	endSpan(span, resp, err)
+
+	return resp, err
+}
+
+func (c *Client) send(ctx context.Context, r *http.Request) (*http.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &CanceledError{Method: r.Method, Path: r.URL.Path, Err: err}
+	}
+
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	c.logRequest(r)
+
+	resp, err := c.c.Do(r.WithContext(ctx))
+	if err != nil {
+		c.logFailure(r, start, err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, c.canceledError(r, ctxErr)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		c.logResponse(r, resp, start, body)
+		return nil, newHTTPError(resp, body)
+	}
+
+	var aer autheticationErrorResponse
+
+	// We read and save the response body so that if we don't have error messages
+	// we can set it again for future usage
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		c.logFailure(r, start, err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, c.canceledError(r, ctxErr)
+		}
+		return nil, err
+	}
+	c.logResponse(r, resp, start, b)
+
+	err = json.Unmarshal(b, &aer)
+	if err == nil && aer.ErrNo == 0 && aer.Error != "" {
+		return nil, &AuthenticationError{
+			ErrNo:   aer.ErrNo,
+			Message: aer.Error,
+		}
+	}
+
+	resp.Body = &responseBody{
+		Reader: bytes.NewReader(b),
+	}
+
+	return resp, nil
+}
+
+// newHTTPError describes a non 2xx response, keeping the start of its body.
+func newHTTPError(resp *http.Response, body []byte) error {
+	e := HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return &NotFoundError{Kind: "Resource", ID: resp.Request.URL.Path}
+	case http.StatusTooManyRequests:
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &RateLimitError{HTTPError: e, RetryAfter: time.Duration(retryAfter) * time.Second}
+	}
+
+	return &e
+}
+
+func (c *Client) canceledError(r *http.Request, err error) error {
+	e := &CanceledError{Method: r.Method, Path: r.URL.Path, Err: err}
+	if c.ctx.Err() == nil && err == context.DeadlineExceeded {
+		e.Timeout = c.requestTimeout
+	}
+
+	return e
+}
+
+func (c *Client) get(path string, v url.Values) (*http.Response, error) {
+	r, err := c.newRequest("GET", path, v, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRequest(r)
+}
+
+func (c *Client) put(path string, v url.Values) (*http.Response, error) {
+	return c.sendForm("PUT", path, v)
+}
+
+func (c *Client) post(path string, v url.Values) (*http.Response, error) {
+	return c.sendForm("POST", path, v)
+}
+
+func (c *Client) sendForm(method string, path string, v url.Values) (*http.Response, error) {
+	if c.readOnly {
+		return nil, &ReadOnlyError{Method: method, Path: path}
+	}
+
+	r, err := c.newRequest(method, path, nil, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.doRequest(r)
+}
+
+func (c *Client) delete(path string, v url.Values) (*http.Response, error) {
+	if c.readOnly {
+		return nil, &ReadOnlyError{Method: "DELETE", Path: path}
+	}
+
+	r, err := c.newRequest("DELETE", path, v, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRequest(r)
+}
+
+// APIVersion returns the API version the client speaks
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
+// Tests returns a client that implements the `Tests` API.
+func (c *Client) Tests() Tests {
+	c.testsOnce.Do(func() {
+		if c.apiVersion == APIVersionV1 {
+			c.testsClient = &v1Tests{client: c}
+		} else {
+			c.testsClient = newTests(c)
+		}
+	})
+
+	return c.testsClient
+}
+
+// ContactGroups returns a client that implements the `ContactGroups` API.
+func (c *Client) ContactGroups() ContactGroups {
+	if c.apiVersion == APIVersionV1 {
+		return &v1ContactGroups{client: c}
+	}
+
+	return NewContactGroups(c)
+}
+
+// Locations returns a client that implements the `Locations` API.
+func (c *Client) Locations() Locations {
+	if c.apiVersion == APIVersionV1 {
+		return &v1Locations{client: c}
+	}
+
+	return NewLocations(c)
+}
@@ -0,0 +1,140 @@
+package statuscake
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type unexpectedHTTPClient struct {
+	t *testing.T
+}
+
+func (c *unexpectedHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	c.t.Fatalf("unexpected %s request to %s", r.Method, r.URL)
+	return nil, nil
+}
+
+func TestClient_readOnly(t *testing.T) {
+	c, err := New(Auth{Username: "user", Apikey: "key"}, WithReadOnly(true))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c.c = &unexpectedHTTPClient{t: t}
+
+	if _, err := c.put("/Tests/Update", url.Values{}); err == nil {
+		t.Fatalf("expected PUT to be refused")
+	} else if _, ok := err.(*ReadOnlyError); !ok {
+		t.Fatalf("expected a ReadOnlyError, got %T: %s", err, err)
+	}
+
+	if err := c.Tests().Delete(1); err == nil {
+		t.Fatalf("expected DELETE to be refused")
+	}
+}
+
+type cannedHTTPClient struct {
+	body string
+}
+
+func (c *cannedHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(c.body)),
+	}, nil
+}
+
+func TestClient_userAgent(t *testing.T) {
+	for _, ua := range []string{"", "terraform-provider-statuscake/1.0.0 terraform/0.12.6"} {
+		c, err := New(Auth{Username: "user", Apikey: "key"}, WithUserAgent(ua))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		r, err := c.WithContext(c.Context()).newRequest("GET", "/Tests", nil, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if got := r.Header.Get("User-Agent"); got != ua {
+			t.Errorf("expected User-Agent %q, got %q", ua, got)
+		}
+	}
+}
+
+func TestClient_credentials(t *testing.T) {
+	current := Auth{Username: "user", Apikey: "key"}
+	c, err := New(current, WithCredentials(func() (Auth, error) { return current, nil }))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r, _ := c.newRequest("GET", "/Tests", nil, nil)
+	if r.Header.Get("Username") != "user" || r.Header.Get("API") != "key" || r.Header.Get("Authorization") != "" {
+		t.Errorf("expected Username and API headers, got %v", r.Header)
+	}
+
+	current = Auth{Token: "token"}
+	r, _ = c.newRequest("GET", "/Tests", nil, nil)
+	if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("API") != "" {
+		t.Errorf("expected the refreshed bearer token, got %v", r.Header)
+	}
+
+	c, _ = New(current, WithCredentials(func() (Auth, error) { return Auth{}, fmt.Errorf("expired") }))
+	if _, err := c.newRequest("GET", "/Tests", nil, nil); err == nil {
+		t.Error("expected the credentials error")
+	}
+}
+
+func TestClient_Account(t *testing.T) {
+	c, err := New(Auth{Username: "user", Apikey: "key"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	c.c = &cannedHTTPClient{body: `{"Success": true, "Details": {"Username": "user", "Plan": "SUPERIOR", "MaxTests": 100}}`}
+	account, err := c.Account()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if account.Username != "user" || account.Plan != "SUPERIOR" || account.MaxTests != 100 {
+		t.Errorf("unexpected account %+v", account)
+	}
+
+	c.c = &cannedHTTPClient{body: `{"Success": false, "Message": "Account Locked"}`}
+	_, err = c.Account()
+	if e, ok := err.(*AuthenticationError); !ok || e.Message != "Account Locked" {
+		t.Errorf("expected an AuthenticationError, got %T: %v", err, err)
+	}
+}
+
+func TestTests_AllListed(t *testing.T) {
+	c, err := New(Auth{Username: "user", Apikey: "key"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c.c = &cannedHTTPClient{body: `[
+		{"TestID": 1, "WebsiteName": "api", "Paused": false, "TestTags": ["a"]},
+		{"TestID": 2, "WebsiteName": "web", "CheckRate": 60}
+	]`}
+
+	listed, err := c.Tests().AllListed()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("expected 2 tests, got %d", len(listed))
+	}
+	if listed[0].TestID != 1 || listed[0].WebsiteName != "api" || len(listed[0].TestTags) != 1 {
+		t.Fatalf("unexpected first test: %+v", listed[0].Test)
+	}
+	if !listed[0].Keys["Paused"] || listed[0].Keys["CheckRate"] {
+		t.Fatalf("unexpected keys for the first test: %v", listed[0].Keys)
+	}
+	if !listed[1].Keys["CheckRate"] || listed[1].CheckRate != 60 {
+		t.Fatalf("unexpected second test: %+v %v", listed[1].Test, listed[1].Keys)
+	}
+}
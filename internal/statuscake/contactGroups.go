@@ -3,32 +3,53 @@ package statuscake
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/google/go-querystring/query"
 	"net/url"
+	"strconv"
 	"strings"
-	"github.com/google/go-querystring/query"
 )
 
-//ContactGroup represent the data received by the API with GET
+// ContactGroup represent the data received by the API with GET
 type ContactGroup struct {
-	GroupName      string   `json:"GroupName"    url:"GroupName,omitempty"`
-	Emails         []string `json:"Emails"`
-	EmailsPut      string                       `url:"Email,omitempty"`
-	Mobiles        string   `json:"Mobiles"      url:"Mobile,omitempty"`
-	Boxcar         string   `json:"Boxcar"       url:"Boxcar,omitempty"`
-	Pushover       string   `json:"Pushover"     url:"Pushover,omitempty"`
-	ContactID      int      `json:"ContactID"    url:"ContactID,omitempty"`
-	DesktopAlert   string   `json:"DesktopAlert" url:"DesktopAlert,omitempty"`
-	PingURL        string   `json:"PingURL"      url:"PingURL,omitempty"`
+	GroupName    string   `json:"GroupName"    url:"GroupName,omitempty"`
+	Emails       []string `json:"Emails"`
+	EmailsPut    string   `url:"Email,omitempty"`
+	Mobiles      string   `json:"Mobiles"      url:"Mobile,omitempty"`
+	Boxcar       string   `json:"Boxcar"       url:"Boxcar,omitempty"`
+	Pushover     string   `json:"Pushover"     url:"Pushover,omitempty"`
+	ContactID    int      `json:"ContactID"    url:"ContactID,omitempty"`
+	DesktopAlert string   `json:"DesktopAlert" url:"DesktopAlert,omitempty"`
+	PingURL      string   `json:"PingURL"      url:"PingURL,omitempty"`
+}
 
+// UnmarshalJSON decodes a ContactGroup, accepting its ID as a number or a
+// string, and its emails as a list or a comma separated string.
+func (cg *ContactGroup) UnmarshalJSON(b []byte) error {
+	type plain ContactGroup
+	aux := struct {
+		*plain
+		Emails    flexStrings `json:"Emails"`
+		Mobiles   flexString  `json:"Mobiles"`
+		ContactID flexInt     `json:"ContactID"`
+	}{plain: (*plain)(cg)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	cg.Emails = aux.Emails
+	cg.Mobiles = string(aux.Mobiles)
+	cg.ContactID = int(aux.ContactID)
+
+	return nil
 }
 
 type Response struct {
-	Success  bool   `json:"Success"`
-	Message  string `json:"Message"`
-	InsertID int    `json:"InsertID"`
+	Success  flexBool `json:"Success"`
+	Message  string   `json:"Message"`
+	InsertID flexInt  `json:"InsertID"`
 }
 
-//ContactGroups represent the actions done wit the API
+// ContactGroups represent the actions done wit the API
 type ContactGroups interface {
 	All() ([]*ContactGroup, error)
 	Detail(int) (*ContactGroup, error)
@@ -44,25 +65,25 @@ func findContactGroup(responses []*ContactGroup, id int) (*ContactGroup, error)
 			return elem, nil
 		}
 	}
-	return response, fmt.Errorf("%d Not found", id)
+	return response, &NotFoundError{Kind: "ContactGroup", ID: strconv.Itoa(id)}
 }
 
-type contactGroups struct{
+type contactGroups struct {
 	client apiClient
 }
 
-//NewContactGroups return a new ssls
+// NewContactGroups return a new ssls
 func NewContactGroups(c apiClient) ContactGroups {
 	return &contactGroups{
 		client: c,
 	}
 }
 
-//All return a list of all the ContactGroup from the API
+// All return a list of all the ContactGroup from the API
 func (tt *contactGroups) All() ([]*ContactGroup, error) {
 	rawResponse, err := tt.client.get("/ContactGroups", nil)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting StatusCake contactGroups: %s", err.Error())
+		return nil, fmt.Errorf("Error getting StatusCake contactGroups: %w", err)
 	}
 	var getResponse []*ContactGroup
 	err = json.NewDecoder(rawResponse.Body).Decode(&getResponse)
@@ -72,7 +93,7 @@ func (tt *contactGroups) All() ([]*ContactGroup, error) {
 	return getResponse, err
 }
 
-//Detail return the ContactGroup corresponding to the id 
+// Detail return the ContactGroup corresponding to the id
 func (tt *contactGroups) Detail(id int) (*ContactGroup, error) {
 	responses, err := tt.All()
 	if err != nil {
@@ -85,51 +106,51 @@ func (tt *contactGroups) Detail(id int) (*ContactGroup, error) {
 	return myContactGroup, nil
 }
 
-//Update update the API with cg and create one if cg.ContactID=0 then return the corresponding ContactGroup
+// Update update the API with cg and create one if cg.ContactID=0 then return the corresponding ContactGroup
 func (tt *contactGroups) Update(cg *ContactGroup) (*ContactGroup, error) {
 
-	if(cg.ContactID == 0){
+	if cg.ContactID == 0 {
 		return tt.Create(cg)
 	}
-	cg.EmailsPut=strings.Join(cg.Emails,",")
+	cg.EmailsPut = strings.Join(cg.Emails, ",")
 	var v url.Values
 
 	v, _ = query.Values(*cg)
-	
+
 	rawResponse, err := tt.client.put("/ContactGroups/Update", v)
 	if err != nil {
-		return nil, fmt.Errorf("Error creating StatusCake ContactGroup: %s", err.Error())
+		return nil, fmt.Errorf("Error creating StatusCake ContactGroup: %w", err)
 	}
-	
+
 	var response Response
 	err = json.NewDecoder(rawResponse.Body).Decode(&response)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if !response.Success {
-		return nil, fmt.Errorf("%s", response.Message)
+		return nil, newUpdateError(response.Message, nil)
 	}
 
 	return cg, nil
 }
 
-//Delete delete the ContactGroup which ID is id
+// Delete delete the ContactGroup which ID is id
 func (tt *contactGroups) Delete(id int) error {
 	_, err := tt.client.delete("/ContactGroups/Update", url.Values{"ContactID": {fmt.Sprint(id)}})
 	return err
 }
 
-//CreatePartial create the ContactGroup whith the data in cg and return the ContactGroup created
+// CreatePartial create the ContactGroup whith the data in cg and return the ContactGroup created
 func (tt *contactGroups) Create(cg *ContactGroup) (*ContactGroup, error) {
-	cg.ContactID=0
-	cg.EmailsPut=strings.Join(cg.Emails,",")
+	cg.ContactID = 0
+	cg.EmailsPut = strings.Join(cg.Emails, ",")
 	var v url.Values
 	v, _ = query.Values(*cg)
-	
+
 	rawResponse, err := tt.client.put("/ContactGroups/Update", v)
 	if err != nil {
-		return nil, fmt.Errorf("Error creating StatusCake ContactGroup: %s", err.Error())
+		return nil, fmt.Errorf("Error creating StatusCake ContactGroup: %w", err)
 	}
 
 	var response Response
@@ -139,11 +160,10 @@ func (tt *contactGroups) Create(cg *ContactGroup) (*ContactGroup, error) {
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("%s", response.Message)
+		return nil, newUpdateError(response.Message, nil)
 	}
-	
-	cg.ContactID = response.InsertID
-	
-	return cg,nil
-}
 
+	cg.ContactID = int(response.InsertID)
+
+	return cg, nil
+}
@@ -0,0 +1,76 @@
+package statuscake
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newHangingServer() (*httptest.Server, chan struct{}) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+
+	return srv, release
+}
+
+func TestClient_requestTimeout(t *testing.T) {
+	srv, release := newHangingServer()
+	defer srv.Close()
+	defer close(release)
+
+	c, err := New(Auth{Username: "user", Apikey: "key"}, WithBaseURL(srv.URL), WithRequestTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = c.Tests().Detail(1)
+	e, ok := err.(*CanceledError)
+	if !ok {
+		t.Fatalf("expected a CanceledError, got %T: %v", err, err)
+	}
+	if e.Timeout != 50*time.Millisecond {
+		t.Fatalf("expected the error to report the request timeout, got %s", e)
+	}
+}
+
+func TestClient_cancel(t *testing.T) {
+	srv, release := newHangingServer()
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := New(Auth{Username: "user", Apikey: "key"}, WithBaseURL(srv.URL), WithContext(ctx))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := c.Tests().All()
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if e, ok := err.(*CanceledError); !ok || e.Timeout != 0 {
+			t.Fatalf("expected a CanceledError without timeout, got %T: %v", err, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not interrupted by cancelling the context")
+	}
+
+	if _, err := c.Tests().All(); err == nil {
+		t.Fatal("expected requests after cancellation to fail")
+	} else if _, ok := err.(*CanceledError); !ok {
+		t.Fatalf("expected a CanceledError, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,182 @@
+package statuscake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIError implements the error interface an it's used when the API response has errors.
+type APIError interface {
+	APIError() string
+}
+
+// maxErrorBody is how much of a response body an HTTPError keeps, enough
+// for the JSON errors of the v1 API
+const maxErrorBody = 4096
+
+// HTTPError is returned when the API answers with a non 2xx status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// Body is the start of the response body, which usually explains the
+	// status
+	Body string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP error: %d - %s", e.StatusCode, e.Status)
+	}
+
+	return fmt.Sprintf("HTTP error: %d - %s: %s", e.StatusCode, e.Status, e.Body)
+}
+
+// RateLimitError is returned when the API answers 429 Too Many Requests.
+// RetryAfter is zero when the API did not say how long to wait.
+type RateLimitError struct {
+	HTTPError
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited by the StatusCake API, retry after %s", e.RetryAfter)
+	}
+
+	return "rate limited by the StatusCake API"
+}
+
+// NotFoundError is returned when the object asked for does not exist.
+type NotFoundError struct {
+	// Kind names the type of object, such as "Test"
+	Kind string
+	ID   string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Kind, e.ID)
+}
+
+// ValidationError is a map where the key is the invalid field and the value is a message describing why the field is invalid.
+type ValidationError map[string]string
+
+func (e ValidationError) Error() string {
+	var messages []string
+
+	for _, k := range e.Fields() {
+		m := fmt.Sprintf("%s %s", k, e[k])
+		messages = append(messages, m)
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// Fields returns the invalid field names, sorted
+func (e ValidationError) Fields() []string {
+	fields := make([]string, 0, len(e))
+	for k := range e {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// UpdateError is returned when the API refuses a create or update. Issues
+// holds the problems reported against a single API field, and Details any
+// other problems the API listed.
+type UpdateError struct {
+	Message string
+	Issues  ValidationError
+	Details []string
+}
+
+func newUpdateError(message string, issues interface{}) *UpdateError {
+	e := &UpdateError{Message: message, Issues: make(ValidationError)}
+
+	switch issues := issues.(type) {
+	case map[string]interface{}:
+		for k, v := range issues {
+			e.Issues[k] = fmt.Sprint(v)
+		}
+	case []interface{}:
+		for _, v := range issues {
+			e.Details = append(e.Details, fmt.Sprint(v))
+		}
+	case nil:
+	default:
+		e.Details = append(e.Details, fmt.Sprint(issues))
+	}
+
+	return e
+}
+
+func (e *UpdateError) Error() string {
+	var messages []string
+
+	if e.Message != "" {
+		messages = append(messages, e.Message)
+	}
+	if len(e.Issues) > 0 {
+		messages = append(messages, e.Issues.Error())
+	}
+	messages = append(messages, e.Details...)
+
+	return strings.Join(messages, ", ")
+}
+
+// APIError returns the error specified in the API response
+func (e *UpdateError) APIError() string {
+	return e.Error()
+}
+
+// DeleteError is returned when the API refuses a delete.
+type DeleteError struct {
+	Message string
+}
+
+func (e *DeleteError) Error() string {
+	return e.Message
+}
+
+// AuthenticationError implements the error interface and it's returned
+// when API responses have authentication errors
+type AuthenticationError struct {
+	ErrNo   int
+	Message string
+}
+
+func (e *AuthenticationError) Error() string {
+	return fmt.Sprintf("%d, %s", e.ErrNo, e.Message)
+}
+
+// ReadOnlyError is returned instead of sending a request that would modify
+// the account when the client is read only.
+type ReadOnlyError struct {
+	Method string
+	Path   string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("refusing to send %s %s: the client is read only", e.Method, e.Path)
+}
+
+// CanceledError is returned when a request is interrupted, either because the
+// client context was cancelled or because the request took longer than the
+// request timeout, in which case Timeout is set.
+type CanceledError struct {
+	Method  string
+	Path    string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *CanceledError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("%s %s timed out after %s", e.Method, e.Path, e.Timeout)
+	}
+
+	return fmt.Sprintf("%s %s was cancelled: %s", e.Method, e.Path, e.Err)
+}
@@ -0,0 +1,62 @@
+package statuscake
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUpdateError(t *testing.T) {
+	e := newUpdateError("Invalid test", map[string]interface{}{
+		"WebsiteURL": "is invalid",
+		"CheckRate":  "must be between 0 and 23999",
+	})
+	if e.Issues["WebsiteURL"] != "is invalid" || e.Issues["CheckRate"] == "" {
+		t.Fatalf("expected issues per field, got %v", e.Issues)
+	}
+	expected := "Invalid test, CheckRate must be between 0 and 23999, WebsiteURL is invalid"
+	if e.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, e.Error())
+	}
+
+	e = newUpdateError("Invalid test", []interface{}{"Too many tests"})
+	if len(e.Issues) != 0 || len(e.Details) != 1 {
+		t.Fatalf("expected a single detail, got %v and %v", e.Issues, e.Details)
+	}
+}
+
+func TestClient_httpErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Tests/Details":
+			http.NotFound(w, r)
+		case "/Tests":
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.Error(w, "TestType is required", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(Auth{Username: "user", Apikey: "key"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = c.Tests().Detail(42)
+	if e, ok := err.(*NotFoundError); !ok || e.Kind != "Test" || e.ID != "42" {
+		t.Fatalf("expected a NotFoundError for test 42, got %T: %v", err, err)
+	}
+
+	_, err = c.Tests().All()
+	if e, ok := err.(*RateLimitError); !ok || e.RetryAfter != 30*time.Second {
+		t.Fatalf("expected a RateLimitError retrying after 30s, got %T: %v", err, err)
+	}
+
+	_, err = c.get("/Locations/json", nil)
+	if e, ok := err.(*HTTPError); !ok || e.StatusCode != 400 || e.Body != "TestType is required" {
+		t.Fatalf("expected an HTTPError with the response body, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,158 @@
+package statuscake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The legacy API is not consistent about how it encodes values: the same
+// field comes back as 3, "3", "", or null depending on the endpoint and on
+// the account. The flex types below decode any of these forms, so a single
+// odd field does not make a whole response unreadable.
+
+// flexInt is an int that also decodes from a quoted number, a boolean,
+// "true"/"false", "" and null.
+type flexInt int
+
+func (i *flexInt) UnmarshalJSON(b []byte) error {
+	s, null, err := flexScalar(b)
+	if err != nil || null {
+		*i = 0
+		return err
+	}
+
+	switch strings.ToLower(s) {
+	case "", "false":
+		*i = 0
+		return nil
+	case "true":
+		*i = 1
+		return nil
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		*i = flexInt(n)
+		return nil
+	}
+	// Whole numbers sometimes come back as 3.0.
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != float64(int(f)) {
+		return fmt.Errorf("cannot decode %s as an integer", b)
+	}
+	*i = flexInt(f)
+
+	return nil
+}
+
+// flexFloat is a float64 that also decodes from a quoted number, "" and null.
+type flexFloat float64
+
+func (f *flexFloat) UnmarshalJSON(b []byte) error {
+	s, null, err := flexScalar(b)
+	if err != nil || null || s == "" {
+		*f = 0
+		return err
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("cannot decode %s as a number", b)
+	}
+	*f = flexFloat(n)
+
+	return nil
+}
+
+// flexBool is a bool that also decodes from 0/1, "0"/"1", "true"/"false",
+// "" and null.
+type flexBool bool
+
+func (v *flexBool) UnmarshalJSON(b []byte) error {
+	s, null, err := flexScalar(b)
+	if err != nil || null {
+		*v = false
+		return err
+	}
+
+	switch strings.ToLower(s) {
+	case "", "0", "false":
+		*v = false
+	case "1", "true":
+		*v = true
+	default:
+		return fmt.Errorf("cannot decode %s as a boolean", b)
+	}
+
+	return nil
+}
+
+// flexString is a string that also decodes from a number and null.
+type flexString string
+
+func (v *flexString) UnmarshalJSON(b []byte) error {
+	s, _, err := flexScalar(b)
+	*v = flexString(s)
+
+	return err
+}
+
+// flexStrings is a list of strings that also decodes from a list of numbers,
+// a comma separated string, a single number, "" and null.
+type flexStrings []string
+
+func (l *flexStrings) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		var items []flexString
+		if err := json.Unmarshal(b, &items); err != nil {
+			return err
+		}
+		list := make([]string, len(items))
+		for i, item := range items {
+			list[i] = string(item)
+		}
+		*l = list
+		return nil
+	}
+
+	s, _, err := flexScalar(b)
+	if err != nil || s == "" {
+		*l = nil
+		return err
+	}
+
+	list := strings.Split(s, ",")
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	*l = list
+
+	return nil
+}
+
+// flexScalar returns the text of a JSON string, number or boolean, and
+// whether it was null.
+func flexScalar(b []byte) (string, bool, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "", false, err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return strings.TrimSpace(v), false, nil
+	case json.Number:
+		return v.String(), false, nil
+	case bool:
+		return strconv.FormatBool(v), false, nil
+	}
+
+	return "", false, fmt.Errorf("cannot decode %s as a single value", b)
+}
@@ -0,0 +1,42 @@
+//go:build go1.18
+// +build go1.18
+
+package statuscake
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// fuzzDecode seeds f with the captured payload, and checks that decoding
+// never panics and that whatever decodes round trips unchanged.
+func fuzzDecode(f *testing.F, seed string, newValue func() interface{}) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", seed))
+	if err != nil {
+		f.Fatalf("err: %s", err)
+	}
+	f.Add(b)
+	f.Add([]byte(`null`))
+	f.Add([]byte(`[{"TestID": "", "Paused": "0", "ContactGroup": "1,2", "Uptime": null}]`))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		checkRoundTrip(t, newValue, b)
+	})
+}
+
+func FuzzDecodeTests(f *testing.F) {
+	fuzzDecode(f, decodeTargets[0].seed, decodeTargets[0].newValue)
+}
+
+func FuzzDecodeTestDetail(f *testing.F) {
+	fuzzDecode(f, decodeTargets[1].seed, decodeTargets[1].newValue)
+}
+
+func FuzzDecodeContactGroups(f *testing.F) {
+	fuzzDecode(f, decodeTargets[2].seed, decodeTargets[2].newValue)
+}
+
+func FuzzDecodeSsls(f *testing.F) {
+	fuzzDecode(f, decodeTargets[3].seed, decodeTargets[3].newValue)
+}
@@ -0,0 +1,224 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFlexInt(t *testing.T) {
+	cases := map[string]int{
+		`3`: 3, `"3"`: 3, `" 3 "`: 3, `3.0`: 3, `"-1"`: -1, `null`: 0, `""`: 0,
+		`true`: 1, `false`: 0, `"true"`: 1, `"false"`: 0,
+	}
+	for in, expected := range cases {
+		var v flexInt
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Errorf("%s: %s", in, err)
+		} else if int(v) != expected {
+			t.Errorf("%s: expected %d, got %d", in, expected, v)
+		}
+	}
+
+	for _, in := range []string{`"abc"`, `3.5`, `[1]`, `{}`} {
+		var v flexInt
+		if err := json.Unmarshal([]byte(in), &v); err == nil {
+			t.Errorf("%s: expected an error, got %d", in, v)
+		}
+	}
+}
+
+func TestFlexFloat(t *testing.T) {
+	cases := map[string]float64{`99.5`: 99.5, `"99.5"`: 99.5, `100`: 100, `null`: 0, `""`: 0}
+	for in, expected := range cases {
+		var v flexFloat
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Errorf("%s: %s", in, err)
+		} else if float64(v) != expected {
+			t.Errorf("%s: expected %g, got %g", in, expected, v)
+		}
+	}
+
+	var v flexFloat
+	if err := json.Unmarshal([]byte(`"up"`), &v); err == nil {
+		t.Errorf("expected an error, got %g", v)
+	}
+}
+
+func TestFlexBool(t *testing.T) {
+	cases := map[string]bool{
+		`true`: true, `false`: false, `1`: true, `0`: false, `"1"`: true, `"0"`: false,
+		`"true"`: true, `"False"`: false, `""`: false, `null`: false,
+	}
+	for in, expected := range cases {
+		var v flexBool
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Errorf("%s: %s", in, err)
+		} else if bool(v) != expected {
+			t.Errorf("%s: expected %t, got %t", in, expected, v)
+		}
+	}
+
+	for _, in := range []string{`2`, `"yes"`, `[]`} {
+		var v flexBool
+		if err := json.Unmarshal([]byte(in), &v); err == nil {
+			t.Errorf("%s: expected an error, got %t", in, v)
+		}
+	}
+}
+
+func TestFlexStrings(t *testing.T) {
+	cases := map[string][]string{
+		`["a","b"]`:  {"a", "b"},
+		`[1, "2"]`:   {"1", "2"},
+		`[]`:         {},
+		`"a, b"`:     {"a", "b"},
+		`"a"`:        {"a"},
+		`12`:         {"12"},
+		`""`:         nil,
+		`null`:       nil,
+		`["a,b", 3]`: {"a,b", "3"},
+	}
+	for in, expected := range cases {
+		var v flexStrings
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Errorf("%s: %s", in, err)
+		} else if !reflect.DeepEqual([]string(v), expected) {
+			t.Errorf("%s: expected %#v, got %#v", in, expected, []string(v))
+		}
+	}
+
+	var v flexStrings
+	if err := json.Unmarshal([]byte(`[{}]`), &v); err == nil {
+		t.Errorf("expected an error, got %#v", v)
+	}
+}
+
+func TestDecode_capturedPayloads(t *testing.T) {
+	var tests []*Test
+	decodeTestdata(t, "tests_list.json", &tests)
+	if len(tests) != 2 {
+		t.Fatalf("expected 2 tests, got %d", len(tests))
+	}
+	if tt := tests[1]; tt.TestID != 4917822 || !tt.Paused || tt.Port != 5432 || tt.Uptime != 99.87 ||
+		tt.Confirmation != 0 || !reflect.DeepEqual(tt.ContactGroup, []string{"43125", "43126"}) || !tt.Processing {
+		t.Errorf("unexpected second test: %+v", tt)
+	}
+	if tests[0].Confirmation != 2 || tests[0].StatusCode != 200 {
+		t.Errorf("unexpected first test: %+v", tests[0])
+	}
+
+	var dr detailResponse
+	decodeTestdata(t, "test_detail.json", &dr)
+	detail := dr.test()
+	if detail.TriggerRate != 5 || detail.Confirmation != 2 || !detail.EnableSSLAlert || detail.Uptime != 100 ||
+		detail.StatusCodes != "200,201,204" || !reflect.DeepEqual(detail.ContactGroup, []string{"43125", "43126"}) {
+		t.Errorf("unexpected test detail: %+v", detail)
+	}
+
+	var groups []*ContactGroup
+	decodeTestdata(t, "contact_groups.json", &groups)
+	if len(groups) != 3 || groups[1].ContactID != 43126 || !reflect.DeepEqual(groups[1].Emails, []string{"dev@example.com"}) ||
+		groups[2].Mobiles != "447700900001" {
+		t.Errorf("unexpected contact groups: %+v %+v %+v", groups[0], groups[1], groups[2])
+	}
+
+	var ssls []*Ssl
+	decodeTestdata(t, "ssl.json", &ssls)
+	if len(ssls) != 2 {
+		t.Fatalf("expected 2 ssl tests, got %d", len(ssls))
+	}
+	if s := ssls[1]; s.ID != "143616" || s.Checkrate != 86400 || !s.AlertReminder || !s.AlertBroken || s.LastReminder != 7 ||
+		!s.Flags["has_pfs"] || !reflect.DeepEqual(s.ContactGroups, []string{"43125", "43126"}) {
+		t.Errorf("unexpected second ssl test: %+v", s)
+	}
+}
+
+func TestSslCreateResponse_id(t *testing.T) {
+	for _, body := range []string{
+		`{"Success": true, "Message": 143615, "Input": {"domain": "https://www.example.com", "checkrate": 86400, "alert_expiry": 1}}`,
+		`{"Success": "1", "Message": "143615", "Input": {"domain": "https://www.example.com", "checkrate": "86400", "alert_expiry": true}}`,
+	} {
+		var r sslCreateResponse
+		if err := json.Unmarshal([]byte(body), &r); err != nil {
+			t.Fatalf("%s: %s", body, err)
+		}
+		if id, err := r.id(); err != nil || id != 143615 {
+			t.Errorf("%s: expected 143615, got %d, %v", body, id, err)
+		}
+		if !bool(r.Success) || r.Input.Checkrate != "86400" || !r.Input.AlertExpiry {
+			t.Errorf("%s: unexpected response %+v", body, r)
+		}
+	}
+}
+
+func decodeTestdata(t *testing.T, name string, v interface{}) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("Error decoding %s: %s", name, err)
+	}
+}
+
+// decodeTargets are the responses with flex fields, each with a captured
+// payload.
+var decodeTargets = []struct {
+	seed     string
+	newValue func() interface{}
+}{
+	{"tests_list.json", func() interface{} { return &[]*Test{} }},
+	{"test_detail.json", func() interface{} { return &detailResponse{} }},
+	{"contact_groups.json", func() interface{} { return &[]*ContactGroup{} }},
+	{"ssl.json", func() interface{} { return &[]*Ssl{} }},
+}
+
+func TestDecode_roundTrip(t *testing.T) {
+	inputs := []string{
+		`null`,
+		`[{"TestID": "", "Paused": "0", "ContactGroup": "1,2", "Uptime": null}]`,
+		`[{"TestID": 3.0, "Paused": true, "ContactGroup": [1, "2"], "Uptime": "99.5"}]`,
+		`{"TestID": "7", "ContactGroups": [{"ID": "1"}], "Confirmation": "", "EnableSSLAlert": 1}`,
+	}
+	for _, target := range decodeTargets {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", target.seed))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for _, in := range append([]string{string(b)}, inputs...) {
+			checkRoundTrip(t, target.newValue, []byte(in))
+		}
+	}
+}
+
+// checkRoundTrip checks that whatever b decodes to survives being encoded and
+// decoded again unchanged.
+func checkRoundTrip(t *testing.T, newValue func() interface{}, b []byte) {
+	v := newValue()
+	if json.Unmarshal(b, v) != nil {
+		return
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		// NaN and infinite floats decode but cannot be encoded.
+		return
+	}
+	again := newValue()
+	if err := json.Unmarshal(encoded, again); err != nil {
+		t.Fatalf("Error decoding %s: %s", encoded, err)
+	}
+	if !reflect.DeepEqual(v, again) {
+		t.Fatalf("decoding %s changed it to %s", encoded, mustMarshal(t, again))
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return b
+}
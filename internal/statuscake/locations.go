@@ -0,0 +1,64 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Location represents a StatusCake testing node
+type Location struct {
+	GUID       string `json:"guid"`
+	ServerCode string `json:"servercode"`
+	Title      string `json:"title"`
+	IP         string `json:"ip"`
+	IPv6       string `json:"ipv6"`
+	CountryISO string `json:"countryiso"`
+	Status     string `json:"status"`
+}
+
+// Locations is a client that implements the `Locations` API.
+type Locations interface {
+	All() ([]*Location, error)
+}
+
+type locations struct {
+	client apiClient
+}
+
+// NewLocations returns a new Locations client
+func NewLocations(c apiClient) Locations {
+	return &locations{
+		client: c,
+	}
+}
+
+// All returns every testing node, sorted by server code
+func (ll *locations) All() ([]*Location, error) {
+	resp, err := ll.client.get("/Locations/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting StatusCake locations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The API returns an object keyed by an internal node number
+	var byKey map[string]*Location
+	err = json.NewDecoder(resp.Body).Decode(&byKey)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]*Location, 0, len(byKey))
+	for _, l := range byKey {
+		locations = append(locations, l)
+	}
+	sortLocations(locations)
+
+	return locations, nil
+}
+
+func sortLocations(locations []*Location) {
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].ServerCode < locations[j].ServerCode
+	})
+}
@@ -0,0 +1,130 @@
+package statuscake
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLoggedBody is how much of a request or response body is logged
+const maxLoggedBody = 4096
+
+const redacted = "REDACTED"
+
+// sensitiveKeys are the headers, query parameters, form fields and JSON keys
+// whose values are never logged, compared in lower case
+var sensitiveKeys = map[string]bool{
+	"api":            true,
+	"apikey":         true,
+	"username":       true,
+	"authorization":  true,
+	"basicpass":      true,
+	"basic_pass":     true,
+	"basic_password": true,
+	"password":       true,
+	"token":          true,
+}
+
+// sensitiveJSON matches a sensitive key and its string value in a JSON body
+var sensitiveJSON = regexp.MustCompile(`("(?i:api|apikey|username|basicpass|basic_pass|basic_password|password|token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// WithWireLogging logs every request and response at DEBUG level, with
+// credentials and passwords redacted and long bodies truncated
+func WithWireLogging(enabled bool) Option {
+	return func(c *Client) {
+		c.wireLogging = enabled
+	}
+}
+
+func (c *Client) logRequest(r *http.Request) {
+	if !c.wireLogging {
+		return
+	}
+
+	var body string
+	if r.GetBody != nil {
+		if rc, err := r.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(rc)
+			rc.Close()
+			body = redactForm(string(b))
+		}
+	}
+
+	path := r.URL.Path
+	if r.URL.RawQuery != "" {
+		path += "?" + redactForm(r.URL.RawQuery)
+	}
+
+	log.Printf("[DEBUG] StatusCake API request: %s %s\nHeaders: %s\nBody: %s",
+		r.Method, path, redactHeaders(r.Header), truncate(body))
+}
+
+func (c *Client) logResponse(r *http.Request, resp *http.Response, start time.Time, body []byte) {
+	if !c.wireLogging {
+		return
+	}
+
+	log.Printf("[DEBUG] StatusCake API response: %s %s: %s in %s\nBody: %s",
+		r.Method, r.URL.Path, resp.Status, time.Since(start).Round(time.Millisecond), truncate(redactJSON(string(body))))
+}
+
+func (c *Client) logFailure(r *http.Request, start time.Time, err error) {
+	if !c.wireLogging {
+		return
+	}
+
+	log.Printf("[DEBUG] StatusCake API request failed: %s %s after %s: %s",
+		r.Method, r.URL.Path, time.Since(start).Round(time.Millisecond), err)
+}
+
+func redactHeaders(h http.Header) string {
+	parts := make([]string, 0, len(h))
+	for k, v := range h {
+		value := strings.Join(v, ",")
+		if sensitiveKeys[strings.ToLower(k)] {
+			value = redacted
+		}
+		parts = append(parts, k+": "+value)
+	}
+
+	sort.Strings(parts)
+
+	return strings.Join(parts, "; ")
+}
+
+// redactForm redacts the sensitive values of a URL encoded query or form.
+// Anything that does not parse is not logged at all.
+func redactForm(encoded string) string {
+	if encoded == "" {
+		return ""
+	}
+
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return "(unparsable, not logged)"
+	}
+	for k := range values {
+		if sensitiveKeys[strings.ToLower(k)] {
+			values.Set(k, redacted)
+		}
+	}
+
+	return values.Encode()
+}
+
+func redactJSON(body string) string {
+	return sensitiveJSON.ReplaceAllString(body, `${1}"`+redacted+`"`)
+}
+
+func truncate(body string) string {
+	if len(body) <= maxLoggedBody {
+		return body
+	}
+
+	return body[:maxLoggedBody] + "... (truncated)"
+}
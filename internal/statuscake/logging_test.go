@@ -0,0 +1,110 @@
+package statuscake
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
+)
+
+func TestClient_wireLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Tests" {
+			w.Write([]byte(`[{"TestID": 1, "WebsiteName": "` + strings.Repeat("x", 2*maxLoggedBody) + `"}]`))
+			return
+		}
+		w.Write([]byte(`{"Success": true, "InsertID": 7, "BasicPass": "s3cret-in-response"}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c, err := New(Auth{Username: "someone", Apikey: "very-secret-key"}, WithBaseURL(srv.URL), WithWireLogging(true))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := c.Tests().Update(&Test{WebsiteName: "api", WebsiteURL: "https://example.com", BasicUser: "admin", BasicPass: "s3cret"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := c.Tests().All(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"very-secret-key", "someone", "s3cret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("expected %q to be redacted from:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"PUT /Tests/Update", "BasicUser=admin", "BasicPass=REDACTED", "Api: REDACTED", "Username: REDACTED", "200 OK", "GET /Tests", "(truncated)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestClient_wireLoggingV1(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c := newV1TestClient(t, srv, WithWireLogging(true))
+	test := &Test{WebsiteName: "api", WebsiteURL: "https://example.com", TestType: "HTTP", BasicUser: "admin", BasicPass: "s3cret"}
+	created, err := c.Tests().Update(test)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	test.TestID = created.TestID
+	if _, err := c.Tests().Update(test); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if got := redactJSON(`{"basic_password": "s3cret", "password": "s3cret"}`); strings.Contains(got, "s3cret") {
+		t.Errorf("expected the passwords to be redacted from %s", got)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{statuscaketest.Token, "s3cret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("expected %q to be redacted from:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"POST /v1/uptime", "PUT /v1/uptime/", "basic_username=admin", "basic_password=REDACTED", "Authorization: REDACTED"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestClient_wireLoggingDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c, err := New(Auth{Username: "someone", Apikey: "key"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := c.Tests().All(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing to be logged, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,97 @@
+package statuscake
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
+)
+
+// TestClient_concurrent shares one client between goroutines sending create,
+// read, update and delete traffic to a stand-in server. Run it with -race.
+func TestClient_concurrent(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	c, err := New(Auth{Username: statuscaketest.Username, Apikey: statuscaketest.Apikey}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	const workers = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := exerciseClient(c, i); err != nil {
+				errs <- fmt.Errorf("worker %d: %s", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	tests, err := c.Tests().All()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(tests) != 0 {
+		t.Fatalf("expected every test to be deleted, %d left", len(tests))
+	}
+}
+
+func exerciseClient(c *Client, i int) error {
+	name := fmt.Sprintf("test-%d", i)
+
+	created, err := c.Tests().Update(&Test{WebsiteName: name, WebsiteURL: "https://example.com", TestType: "HTTP", CheckRate: 300})
+	if err != nil {
+		return fmt.Errorf("create: %s", err)
+	}
+
+	created.CheckRate = 60
+	created.TestTags = []string{name}
+	if _, err := c.Tests().Update(created); err != nil {
+		return fmt.Errorf("update: %s", err)
+	}
+
+	detail, err := c.Tests().Detail(created.TestID)
+	if err != nil {
+		return fmt.Errorf("detail: %s", err)
+	}
+	if detail.WebsiteName != name || detail.CheckRate != 60 {
+		return fmt.Errorf("detail returned %s every %ds, expected %s every 60s", detail.WebsiteName, detail.CheckRate, name)
+	}
+
+	if _, err := c.Tests().AllListed(); err != nil {
+		return fmt.Errorf("list: %s", err)
+	}
+
+	group, err := NewContactGroups(c).Create(&ContactGroup{GroupName: name, Emails: []string{name + "@example.com"}})
+	if err != nil {
+		return fmt.Errorf("create contact group: %s", err)
+	}
+	if _, err := NewContactGroups(c).Detail(group.ContactID); err != nil {
+		return fmt.Errorf("contact group detail: %s", err)
+	}
+	if err := NewContactGroups(c).Delete(group.ContactID); err != nil {
+		return fmt.Errorf("delete contact group: %s", err)
+	}
+
+	if _, err := NewLocations(c).All(); err != nil {
+		return fmt.Errorf("locations: %s", err)
+	}
+
+	if err := c.Tests().Delete(created.TestID); err != nil {
+		return fmt.Errorf("delete: %s", err)
+	}
+
+	return nil
+}
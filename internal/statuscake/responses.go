@@ -0,0 +1,110 @@
+package statuscake
+
+import (
+	"strconv"
+	"strings"
+)
+
+type autheticationErrorResponse struct {
+	ErrNo int
+	Error string
+}
+
+type updateResponse struct {
+	Issues   interface{} `json:"Issues"`
+	Success  bool        `json:"Success"`
+	Message  string      `json:"Message"`
+	InsertID flexInt     `json:"InsertID"`
+}
+
+type deleteResponse struct {
+	Success bool   `json:"Success"`
+	Error   string `json:"Error"`
+}
+
+type contactGroupDetailResponse struct {
+	ID    flexInt `json:"ID"`
+	Name  string  `json:"Name"`
+	Email string  `json:"Email"`
+}
+
+type detailResponse struct {
+	Method           string                       `json:"Method"`
+	TestID           flexInt                      `json:"TestID"`
+	TestType         string                       `json:"TestType"`
+	Paused           flexBool                     `json:"Paused"`
+	WebsiteName      string                       `json:"WebsiteName"`
+	URI              string                       `json:"URI"`
+	ContactID        flexInt                      `json:"ContactID"`
+	ContactGroups    []contactGroupDetailResponse `json:"ContactGroups"`
+	Status           string                       `json:"Status"`
+	Uptime           flexFloat                    `json:"Uptime"`
+	CustomHeader     string                       `json:"CustomHeader"`
+	UserAgent        string                       `json:"UserAgent"`
+	CheckRate        flexInt                      `json:"CheckRate"`
+	Timeout          flexInt                      `json:"Timeout"`
+	LogoImage        string                       `json:"LogoImage"`
+	Confirmation     flexInt                      `json:"Confirmation"`
+	WebsiteHost      string                       `json:"WebsiteHost"`
+	NodeLocations    flexStrings                  `json:"NodeLocations"`
+	FindString       string                       `json:"FindString"`
+	DoNotFind        flexBool                     `json:"DoNotFind"`
+	LastTested       string                       `json:"LastTested"`
+	NextLocation     string                       `json:"NextLocation"`
+	Port             flexInt                      `json:"Port"`
+	Processing       flexBool                     `json:"Processing"`
+	ProcessingState  string                       `json:"ProcessingState"`
+	ProcessingOn     string                       `json:"ProcessingOn"`
+	DownTimes        flexInt                      `json:"DownTimes"`
+	Sensitive        flexBool                     `json:"Sensitive"`
+	TriggerRate      flexInt                      `json:"TriggerRate"`
+	UseJar           flexInt                      `json:"UseJar"`
+	PostRaw          string                       `json:"PostRaw"`
+	FinalEndpoint    string                       `json:"FinalEndpoint"`
+	EnableSSLWarning flexBool                     `json:"EnableSSLWarning"`
+	FollowRedirect   flexBool                     `json:"FollowRedirect"`
+	StatusCodes      flexStrings                  `json:"StatusCodes"`
+	Tags             flexStrings                  `json:"Tags"`
+	StatusCode       flexInt                      `json:"StatusCode"`
+}
+
+func (d *detailResponse) test() *Test {
+	contactGroupIds := make([]string, len(d.ContactGroups))
+	for i, v := range d.ContactGroups {
+		contactGroupIds[i] = strconv.Itoa(int(v.ID))
+	}
+
+	return &Test{
+		TestID:         int(d.TestID),
+		TestType:       d.TestType,
+		Paused:         bool(d.Paused),
+		WebsiteName:    d.WebsiteName,
+		WebsiteURL:     d.URI,
+		CustomHeader:   d.CustomHeader,
+		UserAgent:      d.UserAgent,
+		ContactID:      int(d.ContactID),
+		ContactGroup:   contactGroupIds,
+		Status:         d.Status,
+		Uptime:         float64(d.Uptime),
+		CheckRate:      int(d.CheckRate),
+		Timeout:        int(d.Timeout),
+		LogoImage:      d.LogoImage,
+		Confirmation:   int(d.Confirmation),
+		WebsiteHost:    d.WebsiteHost,
+		NodeLocations:  d.NodeLocations,
+		FindString:     d.FindString,
+		DoNotFind:      bool(d.DoNotFind),
+		Port:           int(d.Port),
+		TriggerRate:    int(d.TriggerRate),
+		UseJar:         int(d.UseJar),
+		PostRaw:        d.PostRaw,
+		FinalEndpoint:  d.FinalEndpoint,
+		EnableSSLAlert: bool(d.EnableSSLWarning),
+		FollowRedirect: bool(d.FollowRedirect),
+		StatusCodes:    strings.Join(d.StatusCodes[:], ","),
+		TestTags:       d.Tags,
+		Processing:     bool(d.Processing),
+		LastTested:     d.LastTested,
+		StatusCode:     int(d.StatusCode),
+	}
+}
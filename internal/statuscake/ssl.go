@@ -4,13 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"strings"
 	"strconv"
-	
+	"strings"
+
 	"github.com/google/go-querystring/query"
 )
 
-//Ssl represent the data received by the API with GET
+// Ssl represent the data received by the API with GET
 type Ssl struct {
 	ID             string              `json:"id"                 url:"id,omitempty"`
 	Domain         string              `json:"domain"             url:"domain,omitempty"`
@@ -36,7 +36,48 @@ type Ssl struct {
 	LastUpdatedUtc string              `json:"last_updated_utc"`
 }
 
-//PartialSsl represent  a ssl test creation or modification
+// UnmarshalJSON decodes an Ssl, accepting numbers, booleans and lists in any
+// of the forms the API returns them in.
+func (s *Ssl) UnmarshalJSON(b []byte) error {
+	type plain Ssl
+	aux := struct {
+		*plain
+		ID            flexString          `json:"id"`
+		Checkrate     flexInt             `json:"checkrate"`
+		AlertReminder flexBool            `json:"alert_reminder"`
+		AlertExpiry   flexBool            `json:"alert_expiry"`
+		AlertBroken   flexBool            `json:"alert_broken"`
+		AlertMixed    flexBool            `json:"alert_mixed"`
+		Paused        flexBool            `json:"paused"`
+		Flags         map[string]flexBool `json:"flags"`
+		ContactGroups flexStrings         `json:"contact_groups"`
+		LastReminder  flexInt             `json:"last_reminder"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	s.ID = string(aux.ID)
+	s.Checkrate = int(aux.Checkrate)
+	s.AlertReminder = bool(aux.AlertReminder)
+	s.AlertExpiry = bool(aux.AlertExpiry)
+	s.AlertBroken = bool(aux.AlertBroken)
+	s.AlertMixed = bool(aux.AlertMixed)
+	s.Paused = bool(aux.Paused)
+	s.Flags = nil
+	if aux.Flags != nil {
+		s.Flags = make(map[string]bool, len(aux.Flags))
+		for k, v := range aux.Flags {
+			s.Flags[k] = bool(v)
+		}
+	}
+	s.ContactGroups = aux.ContactGroups
+	s.LastReminder = int(aux.LastReminder)
+
+	return nil
+}
+
+// PartialSsl represent  a ssl test creation or modification
 type PartialSsl struct {
 	ID             int
 	Domain         string
@@ -61,6 +102,35 @@ type createSsl struct {
 	AlertMixed     bool   `url:"alert_mixed"    json:"alert_mixed"`
 }
 
+// UnmarshalJSON decodes the ssl test echoed back by a create, accepting its
+// check rate as a number or a string, and its alerts as numbers or booleans.
+func (c *createSsl) UnmarshalJSON(b []byte) error {
+	type plain createSsl
+	aux := struct {
+		*plain
+		ID             flexInt    `json:"ID"`
+		Checkrate      flexString `json:"checkrate"`
+		ContactGroupsC flexString `json:"contact_groups"`
+		AlertExpiry    flexBool   `json:"alert_expiry"`
+		AlertReminder  flexBool   `json:"alert_reminder"`
+		AlertBroken    flexBool   `json:"alert_broken"`
+		AlertMixed     flexBool   `json:"alert_mixed"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	c.ID = int(aux.ID)
+	c.Checkrate = string(aux.Checkrate)
+	c.ContactGroupsC = string(aux.ContactGroupsC)
+	c.AlertExpiry = bool(aux.AlertExpiry)
+	c.AlertReminder = bool(aux.AlertReminder)
+	c.AlertBroken = bool(aux.AlertBroken)
+	c.AlertMixed = bool(aux.AlertMixed)
+
+	return nil
+}
+
 type updateSsl struct {
 	ID             int    `url:"id"`
 	Domain         string `url:"domain"         json:"domain"`
@@ -73,19 +143,31 @@ type updateSsl struct {
 	AlertMixed     bool   `url:"alert_mixed"    json:"alert_mixed"`
 }
 
-
 type sslUpdateResponse struct {
-	Success bool   `json:"Success"`
+	Success flexBool    `json:"Success"`
 	Message interface{} `json:"Message"`
 }
 
 type sslCreateResponse struct {
-	Success bool   `json:"Success"`
+	Success flexBool    `json:"Success"`
 	Message interface{} `json:"Message"`
-	Input createSsl `json:"Input"`
+	Input   createSsl   `json:"Input"`
+}
+
+// id returns the ID of the created ssl test, which a successful create
+// returns as its Message.
+func (r *sslCreateResponse) id() (int, error) {
+	switch m := r.Message.(type) {
+	case float64:
+		return int(m), nil
+	case string:
+		return strconv.Atoi(strings.TrimSpace(m))
+	}
+
+	return 0, fmt.Errorf("Error reading the ID of the created ssl test from %v", r.Message)
 }
 
-//Ssls represent the actions done wit the API
+// Ssls represent the actions done wit the API
 type Ssls interface {
 	All() ([]*Ssl, error)
 	completeSsl(*PartialSsl) (*Ssl, error)
@@ -108,7 +190,7 @@ func findSsl(responses []*Ssl, id string) (*Ssl, error) {
 			return elem, nil
 		}
 	}
-	return response, fmt.Errorf("%s Not found", id)
+	return response, &NotFoundError{Kind: "Ssl", ID: id}
 }
 
 func (tt *ssls) completeSsl(s *PartialSsl) (*Ssl, error) {
@@ -116,45 +198,45 @@ func (tt *ssls) completeSsl(s *PartialSsl) (*Ssl, error) {
 	if err != nil {
 		return nil, err
 	}
-	(*full).ContactGroups = strings.Split((*s).ContactGroupsC,",")
+	(*full).ContactGroups = strings.Split((*s).ContactGroupsC, ",")
 	return full, nil
 }
 
-//Partial return a PartialSsl corresponding to the Ssl
-func Partial(s *Ssl) (*PartialSsl,error) {
-	if s==nil {
-		return nil,fmt.Errorf("s is nil")
+// Partial return a PartialSsl corresponding to the Ssl
+func Partial(s *Ssl) (*PartialSsl, error) {
+	if s == nil {
+		return nil, fmt.Errorf("s is nil")
 	}
-	id,err:=strconv.Atoi(s.ID)
-	if(err!=nil){
-		return nil,err
+	id, err := strconv.Atoi(s.ID)
+	if err != nil {
+		return nil, err
 	}
 	return &PartialSsl{
-		ID: id,
-		Domain: s.Domain,
-		Checkrate: strconv.Itoa(s.Checkrate),
+		ID:             id,
+		Domain:         s.Domain,
+		Checkrate:      strconv.Itoa(s.Checkrate),
 		ContactGroupsC: s.ContactGroupsC,
-		AlertReminder: s.AlertReminder,
-		AlertExpiry: s.AlertExpiry,
-		AlertBroken: s.AlertBroken,
-		AlertMixed: s.AlertMixed,
-		AlertAt: s.AlertAt,
-	},nil
-	
+		AlertReminder:  s.AlertReminder,
+		AlertExpiry:    s.AlertExpiry,
+		AlertBroken:    s.AlertBroken,
+		AlertMixed:     s.AlertMixed,
+		AlertAt:        s.AlertAt,
+	}, nil
+
 }
 
 type ssls struct {
 	client apiClient
 }
 
-//NewSsls return a new ssls
+// NewSsls return a new ssls
 func NewSsls(c apiClient) Ssls {
 	return &ssls{
 		client: c,
 	}
 }
 
-//All return a list of all the ssl from the API
+// All return a list of all the ssl from the API
 func (tt *ssls) All() ([]*Ssl, error) {
 	rawResponse, err := tt.client.get("/SSL", nil)
 	if err != nil {
@@ -165,15 +247,15 @@ func (tt *ssls) All() ([]*Ssl, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	for ssl := range getResponse {
 		consolidateSsl(getResponse[ssl])
 	}
-	
+
 	return getResponse, err
 }
 
-//Detail return the ssl corresponding to the id 
+// Detail return the ssl corresponding to the id
 func (tt *ssls) Detail(id string) (*Ssl, error) {
 	responses, err := tt.All()
 	if err != nil {
@@ -186,46 +268,45 @@ func (tt *ssls) Detail(id string) (*Ssl, error) {
 	return mySsl, nil
 }
 
-//Update update the API with s and create one if s.ID=0 then return the corresponding Ssl
+// Update update the API with s and create one if s.ID=0 then return the corresponding Ssl
 func (tt *ssls) Update(s *PartialSsl) (*Ssl, error) {
 	var err error
 	s, err = tt.UpdatePartial(s)
-	if err!= nil {
+	if err != nil {
 		return nil, err
 	}
 	return tt.completeSsl(s)
 }
 
-//UpdatePartial update the API with s and create one if s.ID=0 then return the corresponding PartialSsl
+// UpdatePartial update the API with s and create one if s.ID=0 then return the corresponding PartialSsl
 func (tt *ssls) UpdatePartial(s *PartialSsl) (*PartialSsl, error) {
 
-	if((*s).ID == 0){
+	if (*s).ID == 0 {
 		return tt.CreatePartial(s)
 	}
 	var v url.Values
 
 	v, _ = query.Values(updateSsl(*s))
-	
+
 	rawResponse, err := tt.client.put("/SSL/Update", v)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating StatusCake Ssl: %s", err.Error())
 	}
-	
+
 	var updateResponse sslUpdateResponse
 	err = json.NewDecoder(rawResponse.Body).Decode(&updateResponse)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if !updateResponse.Success {
-		return nil, fmt.Errorf("%s", updateResponse.Message.(string))
+		return nil, fmt.Errorf("%v", updateResponse.Message)
 	}
 
-
 	return s, nil
 }
 
-//Delete delete the ssl which ID is id
+// Delete delete the ssl which ID is id
 func (tt *ssls) Delete(id string) error {
 	_, err := tt.client.delete("/SSL/Update", url.Values{"id": {fmt.Sprint(id)}})
 	if err != nil {
@@ -235,22 +316,22 @@ func (tt *ssls) Delete(id string) error {
 	return nil
 }
 
-//Create create the ssl whith the data in s and return the Ssl created
+// Create create the ssl whith the data in s and return the Ssl created
 func (tt *ssls) Create(s *PartialSsl) (*Ssl, error) {
 	var err error
 	s, err = tt.CreatePartial(s)
-	if err!= nil {
+	if err != nil {
 		return nil, err
 	}
 	return tt.completeSsl(s)
 }
 
-//CreatePartial create the ssl whith the data in s and return the PartialSsl created
+// CreatePartial create the ssl whith the data in s and return the PartialSsl created
 func (tt *ssls) CreatePartial(s *PartialSsl) (*PartialSsl, error) {
-	(*s).ID=0
+	(*s).ID = 0
 	var v url.Values
 	v, _ = query.Values(createSsl(*s))
-	
+
 	rawResponse, err := tt.client.put("/SSL/Update", v)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating StatusCake Ssl: %s", err.Error())
@@ -263,11 +344,14 @@ func (tt *ssls) CreatePartial(s *PartialSsl) (*PartialSsl, error) {
 	}
 
 	if !createResponse.Success {
-		return nil, fmt.Errorf("%s", createResponse.Message.(string))
+		return nil, fmt.Errorf("%v", createResponse.Message)
+	}
+	id, err := createResponse.id()
+	if err != nil {
+		return nil, err
 	}
 	*s = PartialSsl(createResponse.Input)
-	(*s).ID = int(createResponse.Message.(float64))
-	
-	return s,nil
-}
+	(*s).ID = id
 
+	return s, nil
+}
@@ -54,7 +54,7 @@ type Test struct {
 	// A URL to ping if a site goes down.
 	PingURL string `json:"PingURL" querystring:"PingURL"`
 
-	Confirmation int `json:"Confirmation,string" querystring:"Confirmation"`
+	Confirmation int `json:"Confirmation" querystring:"Confirmation"`
 
 	// The number of seconds between checks.
 	CheckRate int `json:"CheckRate" querystring:"CheckRate"`
@@ -115,6 +115,74 @@ type Test struct {
 
 	// Use to specify whether redirects should be followed
 	FollowRedirect bool `json:"FollowRedirect" querystring:"FollowRedirect"`
+
+	// Whether the test is waiting for a check to run, e.g. right after it was created or updated
+	Processing bool `json:"Processing"`
+
+	// When the test was last checked
+	LastTested string `json:"LastTested"`
+
+	// HTTP status code returned by the last check
+	StatusCode int `json:"StatusCode"`
+}
+
+// UnmarshalJSON decodes a Test, accepting numbers, booleans and lists in any
+// of the forms the API returns them in.
+func (t *Test) UnmarshalJSON(b []byte) error {
+	type plain Test
+	aux := struct {
+		*plain
+		TestID         flexInt     `json:"TestID"`
+		Paused         flexBool    `json:"Paused"`
+		Port           flexInt     `json:"Port"`
+		ContactID      flexInt     `json:"ContactID"`
+		ContactGroup   flexStrings `json:"ContactGroup"`
+		Uptime         flexFloat   `json:"Uptime"`
+		NodeLocations  flexStrings `json:"NodeLocations"`
+		Timeout        flexInt     `json:"Timeout"`
+		Confirmation   flexInt     `json:"Confirmation"`
+		CheckRate      flexInt     `json:"CheckRate"`
+		Public         flexInt     `json:"Public"`
+		Branding       flexInt     `json:"Branding"`
+		Virus          flexInt     `json:"Virus"`
+		DoNotFind      flexBool    `json:"DoNotFind"`
+		RealBrowser    flexInt     `json:"RealBrowser"`
+		TriggerRate    flexInt     `json:"TriggerRate"`
+		TestTags       flexStrings `json:"TestTags"`
+		UseJar         flexInt     `json:"UseJar"`
+		EnableSSLAlert flexBool    `json:"EnableSSLAlert"`
+		FollowRedirect flexBool    `json:"FollowRedirect"`
+		Processing     flexBool    `json:"Processing"`
+		StatusCode     flexInt     `json:"StatusCode"`
+	}{plain: (*plain)(t)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	t.TestID = int(aux.TestID)
+	t.Paused = bool(aux.Paused)
+	t.Port = int(aux.Port)
+	t.ContactID = int(aux.ContactID)
+	t.ContactGroup = aux.ContactGroup
+	t.Uptime = float64(aux.Uptime)
+	t.NodeLocations = aux.NodeLocations
+	t.Timeout = int(aux.Timeout)
+	t.Confirmation = int(aux.Confirmation)
+	t.CheckRate = int(aux.CheckRate)
+	t.Public = int(aux.Public)
+	t.Branding = int(aux.Branding)
+	t.Virus = int(aux.Virus)
+	t.DoNotFind = bool(aux.DoNotFind)
+	t.RealBrowser = int(aux.RealBrowser)
+	t.TriggerRate = int(aux.TriggerRate)
+	t.TestTags = aux.TestTags
+	t.UseJar = int(aux.UseJar)
+	t.EnableSSLAlert = bool(aux.EnableSSLAlert)
+	t.FollowRedirect = bool(aux.FollowRedirect)
+	t.Processing = bool(aux.Processing)
+	t.StatusCode = int(aux.StatusCode)
+
+	return nil
 }
 
 // Validate checks if the Test is valid. If it's invalid, it returns a ValidationError with all invalid fields. It returns nil otherwise.
@@ -249,6 +317,7 @@ func valueToQueryStringValue(v reflect.Value) string {
 // Tests is a client that implements the `Tests` API.
 type Tests interface {
 	All() ([]*Test, error)
+	AllListed() ([]*ListedTest, error)
 	AllWithFilter(url.Values) ([]*Test, error)
 	Detail(int) (*Test, error)
 	Update(*Test) (*Test, error)
@@ -278,6 +347,49 @@ func (tt *tests) All() ([]*Test, error) {
 	return tests, err
 }
 
+// ListedTest is a test returned by the list endpoint together with the JSON
+// keys the list included for it, since the list omits some of the fields
+// returned by Detail.
+type ListedTest struct {
+	*Test
+	Keys map[string]bool
+}
+
+// AllListed returns every test in the account like All, recording which
+// fields the list response included for each of them.
+func (tt *tests) AllListed() ([]*ListedTest, error) {
+	resp, err := tt.client.get("/Tests", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	listed := make([]*ListedTest, len(raw))
+	for i, r := range raw {
+		var t Test
+		if err := json.Unmarshal(r, &t); err != nil {
+			return nil, err
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(r, &fields); err != nil {
+			return nil, err
+		}
+
+		keys := make(map[string]bool, len(fields))
+		for k := range fields {
+			keys[k] = true
+		}
+		listed[i] = &ListedTest{Test: &t, Keys: keys}
+	}
+
+	return listed, nil
+}
+
 func (tt *tests) AllWithFilter(filterOptions url.Values) ([]*Test, error) {
 	resp, err := tt.client.get("/Tests", filterOptions)
 	if err != nil {
@@ -305,11 +417,11 @@ func (tt *tests) Update(t *Test) (*Test, error) {
 	}
 
 	if !ur.Success {
-		return nil, &updateError{Issues: ur.Issues, Message: ur.Message}
+		return nil, newUpdateError(ur.Message, ur.Issues)
 	}
 
 	t2 := *t
-	t2.TestID = ur.InsertID
+	t2.TestID = int(ur.InsertID)
 
 	return &t2, err
 }
@@ -328,14 +440,19 @@ func (tt *tests) Delete(testID int) error {
 	}
 
 	if !dr.Success {
-		return &deleteError{Message: dr.Error}
+		return &DeleteError{Message: dr.Error}
 	}
 
 	return nil
 }
 
 func (tt *tests) Detail(testID int) (*Test, error) {
+	notFound := &NotFoundError{Kind: "Test", ID: fmt.Sprint(testID)}
+
 	resp, err := tt.client.get("/Tests/Details", url.Values{"TestID": {fmt.Sprint(testID)}})
+	if _, ok := err.(*NotFoundError); ok {
+		return nil, notFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -346,6 +463,9 @@ func (tt *tests) Detail(testID int) (*Test, error) {
 	if err != nil {
 		return nil, err
 	}
+	if dr == nil || dr.TestID == 0 {
+		return nil, notFound
+	}
 
 	return dr.test(), nil
 }
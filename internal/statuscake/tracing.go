@@ -0,0 +1,120 @@
+package statuscake
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"go.opencensus.io/trace"
+)
+
+// WithContext returns a copy of the client whose requests run under ctx
+// instead of the context it was created with, for example so the spans of
+// its requests are children of the span in ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{
+		c:              c.c,
+		baseURL:        c.baseURL,
+		auth:           c.auth,
+		credentials:    c.credentials,
+		readOnly:       c.readOnly,
+		apiVersion:     c.apiVersion,
+		userAgent:      c.userAgent,
+		wireLogging:    c.wireLogging,
+		ctx:            ctx,
+		requestTimeout: c.requestTimeout,
+	}
+}
+
+// Context returns the context the client's requests run under
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type retryCounterKey struct{}
+
+// WithRetryCounter returns a copy of ctx counting retries in counter. The
+// span of every request made under it records how many retries were counted
+// before the request.
+func WithRetryCounter(ctx context.Context, counter *int64) context.Context {
+	return context.WithValue(ctx, retryCounterKey{}, counter)
+}
+
+// CountRetry counts a retry in the counter of ctx, if it has one.
+func CountRetry(ctx context.Context) {
+	if counter, ok := ctx.Value(retryCounterKey{}).(*int64); ok {
+		atomic.AddInt64(counter, 1)
+	}
+}
+
+// startSpan starts the span of a request, as a child of any span in the
+// client context.
+func (c *Client) startSpan(r *http.Request) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(c.ctx, "StatusCake "+r.Method+" "+r.URL.Path, trace.WithSpanKind(trace.SpanKindClient))
+	if !span.IsRecordingEvents() {
+		return ctx, span
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("http.method", r.Method),
+		trace.StringAttribute("statuscake.endpoint", r.URL.Path),
+	)
+	if counter, ok := c.Context().Value(retryCounterKey{}).(*int64); ok {
+		span.AddAttributes(trace.Int64Attribute("statuscake.retries", atomic.LoadInt64(counter)))
+	}
+	values := requestValues(r)
+	for _, key := range []string{"TestID", "ContactID"} {
+		if v := values.Get(key); v != "" && v != "0" {
+			span.AddAttributes(trace.StringAttribute("statuscake."+key, v))
+		}
+	}
+
+	return ctx, span
+}
+
+// endSpan records the outcome of a request on its span.
+func endSpan(span *trace.Span, resp *http.Response, err error) {
+	var httpErr *HTTPError
+	var rateLimit *RateLimitError
+	switch {
+	case resp != nil:
+		span.AddAttributes(trace.Int64Attribute("http.status_code", int64(resp.StatusCode)))
+	case errors.As(err, &rateLimit):
+		span.AddAttributes(trace.Int64Attribute("http.status_code", int64(rateLimit.StatusCode)))
+	case errors.As(err, &httpErr):
+		span.AddAttributes(trace.Int64Attribute("http.status_code", int64(httpErr.StatusCode)))
+	}
+
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// requestValues returns the query of a request merged with its form body.
+func requestValues(r *http.Request) url.Values {
+	values := r.URL.Query()
+	if r.GetBody == nil {
+		return values
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return values
+	}
+	defer body.Close()
+
+	b, _ := ioutil.ReadAll(body)
+	form, _ := url.ParseQuery(string(b))
+	for k, v := range form {
+		values[k] = append(values[k], v...)
+	}
+
+	return values
+}
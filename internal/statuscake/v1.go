@@ -0,0 +1,549 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// v1PageSize is how many objects a v1 list request asks for
+const v1PageSize = 100
+
+// v1TestFields maps the fields of a v1 uptime check, in responses, forms and
+// errors, to the legacy API fields of Test. Errors and listed keys are
+// reported with the legacy names, so callers see the same names on either
+// backend.
+var v1TestFields = map[string]string{
+	"id":                 "TestID",
+	"name":               "WebsiteName",
+	"website_url":        "WebsiteURL",
+	"test_type":          "TestType",
+	"check_rate":         "CheckRate",
+	"contact_groups":     "ContactGroup",
+	"contact_groups_csv": "ContactGroup",
+	"paused":             "Paused",
+	"status":             "Status",
+	"uptime":             "Uptime",
+	"timeout":            "Timeout",
+	"confirmation":       "Confirmation",
+	"trigger_rate":       "TriggerRate",
+	"tags":               "TestTags",
+	"tags_csv":           "TestTags",
+	"servers":            "NodeLocations",
+	"regions":            "NodeLocations",
+	"regions_csv":        "NodeLocations",
+	"status_codes":       "StatusCodes",
+	"status_codes_csv":   "StatusCodes",
+	"custom_header":      "CustomHeader",
+	"user_agent":         "UserAgent",
+	"basic_username":     "BasicUser",
+	"basic_password":     "BasicPass",
+	"find_string":        "FindString",
+	"do_not_find":        "DoNotFind",
+	"follow_redirects":   "FollowRedirect",
+	"enable_ssl_alert":   "EnableSSLAlert",
+	"final_endpoint":     "FinalEndpoint",
+	"post_raw":           "PostRaw",
+	"host":               "WebsiteHost",
+	"port":               "Port",
+	"use_jar":            "UseJar",
+	"last_tested_at":     "LastTested",
+}
+
+// v1ContactGroupFields does the same as v1TestFields for contact groups.
+var v1ContactGroupFields = map[string]string{
+	"id":                "ContactID",
+	"name":              "GroupName",
+	"email_addresses":   "Email",
+	"email_addresses[]": "Email",
+	"mobile_numbers":    "Mobile",
+	"mobile_numbers[]":  "Mobile",
+	"ping_url":          "PingURL",
+}
+
+// v1Uptime is an uptime check of the v1 API.
+type v1Uptime struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	WebsiteURL    string   `json:"website_url"`
+	TestType      string   `json:"test_type"`
+	CheckRate     int      `json:"check_rate"`
+	ContactGroups []string `json:"contact_groups"`
+	Paused        bool     `json:"paused"`
+	Status        string   `json:"status"`
+	Uptime        float64  `json:"uptime"`
+	Timeout       int      `json:"timeout"`
+	Confirmation  int      `json:"confirmation"`
+	TriggerRate   int      `json:"trigger_rate"`
+	Tags          []string `json:"tags"`
+	Servers       []struct {
+		RegionCode string `json:"region_code"`
+	} `json:"servers"`
+	StatusCodes     []string `json:"status_codes"`
+	CustomHeader    string   `json:"custom_header"`
+	UserAgent       string   `json:"user_agent"`
+	FindString      string   `json:"find_string"`
+	DoNotFind       bool     `json:"do_not_find"`
+	FollowRedirects bool     `json:"follow_redirects"`
+	EnableSSLAlert  bool     `json:"enable_ssl_alert"`
+	FinalEndpoint   string   `json:"final_endpoint"`
+	PostRaw         string   `json:"post_raw"`
+	Host            string   `json:"host"`
+	Port            int      `json:"port"`
+	UseJar          bool     `json:"use_jar"`
+	LastTestedAt    string   `json:"last_tested_at"`
+}
+
+func (u *v1Uptime) test() *Test {
+	id, _ := strconv.Atoi(u.ID)
+	regions := make([]string, len(u.Servers))
+	for i, s := range u.Servers {
+		regions[i] = s.RegionCode
+	}
+	useJar := 0
+	if u.UseJar {
+		useJar = 1
+	}
+
+	return &Test{
+		TestID:         id,
+		WebsiteName:    u.Name,
+		WebsiteURL:     u.WebsiteURL,
+		TestType:       u.TestType,
+		CheckRate:      u.CheckRate,
+		ContactGroup:   u.ContactGroups,
+		Paused:         u.Paused,
+		Status:         v1Status(u.Status),
+		Uptime:         u.Uptime,
+		Timeout:        u.Timeout,
+		Confirmation:   u.Confirmation,
+		TriggerRate:    u.TriggerRate,
+		TestTags:       u.Tags,
+		NodeLocations:  regions,
+		StatusCodes:    strings.Join(u.StatusCodes, ","),
+		CustomHeader:   u.CustomHeader,
+		UserAgent:      u.UserAgent,
+		FindString:     u.FindString,
+		DoNotFind:      u.DoNotFind,
+		FollowRedirect: u.FollowRedirects,
+		EnableSSLAlert: u.EnableSSLAlert,
+		FinalEndpoint:  u.FinalEndpoint,
+		PostRaw:        u.PostRaw,
+		WebsiteHost:    u.Host,
+		Port:           u.Port,
+		UseJar:         useJar,
+		LastTested:     u.LastTestedAt,
+	}
+}
+
+// v1Status spells a v1 status like the legacy API, such as "up" as "Up".
+func v1Status(status string) string {
+	if status == "" {
+		return ""
+	}
+
+	return strings.ToUpper(status[:1]) + strings.ToLower(status[1:])
+}
+
+// v1TestValues returns the form creating or updating t. Lists are sent as
+// CSV so that an empty list clears them.
+func v1TestValues(t *Test) url.Values {
+	v := url.Values{
+		"name":               {t.WebsiteName},
+		"website_url":        {t.WebsiteURL},
+		"test_type":          {t.TestType},
+		"check_rate":         {strconv.Itoa(t.CheckRate)},
+		"contact_groups_csv": {strings.Join(t.ContactGroup, ",")},
+		"paused":             {strconv.FormatBool(t.Paused)},
+		"timeout":            {strconv.Itoa(t.Timeout)},
+		"confirmation":       {strconv.Itoa(t.Confirmation)},
+		"trigger_rate":       {strconv.Itoa(t.TriggerRate)},
+		"tags_csv":           {strings.Join(t.TestTags, ",")},
+		"regions_csv":        {strings.Join(t.NodeLocations, ",")},
+		"custom_header":      {t.CustomHeader},
+		"user_agent":         {t.UserAgent},
+		"basic_username":     {t.BasicUser},
+		"basic_password":     {t.BasicPass},
+		"find_string":        {t.FindString},
+		"do_not_find":        {strconv.FormatBool(t.DoNotFind)},
+		"follow_redirects":   {strconv.FormatBool(t.FollowRedirect)},
+		"enable_ssl_alert":   {strconv.FormatBool(t.EnableSSLAlert)},
+		"final_endpoint":     {t.FinalEndpoint},
+		"post_raw":           {t.PostRaw},
+		"host":               {t.WebsiteHost},
+		"use_jar":            {strconv.FormatBool(t.UseJar == 1)},
+	}
+	if t.StatusCodes != "" {
+		v.Set("status_codes_csv", t.StatusCodes)
+	}
+	if t.Port != 0 {
+		v.Set("port", strconv.Itoa(t.Port))
+	}
+
+	return v
+}
+
+// v1Created is the response to a v1 create request.
+type v1Created struct {
+	Data struct {
+		NewID string `json:"new_id"`
+	} `json:"data"`
+}
+
+// v1ErrorResponse is the body of a v1 error.
+type v1ErrorResponse struct {
+	Message string              `json:"message"`
+	Errors  map[string][]string `json:"errors"`
+}
+
+// v1Error turns the JSON error of the v1 API into the error the legacy API
+// would have returned, naming fields by their legacy names.
+func v1Error(err error, fields map[string]string) error {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+
+	var body v1ErrorResponse
+	if json.Unmarshal([]byte(httpErr.Body), &body) != nil {
+		return err
+	}
+
+	switch httpErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &AuthenticationError{Message: body.Message}
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		e := &UpdateError{Message: body.Message, Issues: make(ValidationError)}
+		for field, messages := range body.Errors {
+			if legacy, ok := fields[field]; ok {
+				field = legacy
+			}
+			e.Issues[field] = strings.Join(messages, ", ")
+		}
+		return e
+	}
+
+	return err
+}
+
+// v1List requests every page of a v1 list, calling each with the objects of
+// every page in turn.
+func (c *Client) v1List(path string, filter url.Values, each func(json.RawMessage) error) error {
+	for page := 1; ; page++ {
+		query := url.Values{}
+		for k, v := range filter {
+			query[k] = v
+		}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(v1PageSize))
+
+		resp, err := c.get(path, query)
+		if err != nil {
+			return err
+		}
+
+		var body struct {
+			Data     []json.RawMessage `json:"data"`
+			Metadata struct {
+				PageCount int `json:"page_count"`
+			} `json:"metadata"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, raw := range body.Data {
+			if err := each(raw); err != nil {
+				return err
+			}
+		}
+		if page >= body.Metadata.PageCount {
+			return nil
+		}
+	}
+}
+
+// v1Create posts a create form and returns the ID of the new object.
+func (c *Client) v1Create(path string, v url.Values) (int, error) {
+	resp, err := c.post(path, v)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var created v1Created
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(created.Data.NewID)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected ID %q of the created object", created.Data.NewID)
+	}
+
+	return id, nil
+}
+
+// v1Detail decodes the data of a single v1 object into out.
+func (c *Client) v1Detail(path string, out interface{}) error {
+	resp, err := c.get(path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+
+	return json.NewDecoder(resp.Body).Decode(&body)
+}
+
+type v1Tests struct {
+	client *Client
+}
+
+func (tt *v1Tests) All() ([]*Test, error) {
+	return tt.AllWithFilter(nil)
+}
+
+func (tt *v1Tests) AllListed() ([]*ListedTest, error) {
+	var listed []*ListedTest
+	err := tt.client.v1List("/uptime", nil, func(raw json.RawMessage) error {
+		var u v1Uptime
+		if err := json.Unmarshal(raw, &u); err != nil {
+			return err
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return err
+		}
+
+		keys := make(map[string]bool, len(fields))
+		for k := range fields {
+			if legacy, ok := v1TestFields[k]; ok {
+				keys[legacy] = true
+			}
+		}
+		listed = append(listed, &ListedTest{Test: u.test(), Keys: keys})
+		return nil
+	})
+
+	return listed, v1Error(err, v1TestFields)
+}
+
+func (tt *v1Tests) AllWithFilter(filter url.Values) ([]*Test, error) {
+	var tests []*Test
+	err := tt.client.v1List("/uptime", filter, func(raw json.RawMessage) error {
+		var u v1Uptime
+		if err := json.Unmarshal(raw, &u); err != nil {
+			return err
+		}
+		tests = append(tests, u.test())
+		return nil
+	})
+
+	return tests, v1Error(err, v1TestFields)
+}
+
+func (tt *v1Tests) Detail(testID int) (*Test, error) {
+	var u v1Uptime
+	err := tt.client.v1Detail(fmt.Sprintf("/uptime/%d", testID), &u)
+	if _, ok := err.(*NotFoundError); ok {
+		return nil, &NotFoundError{Kind: "Test", ID: fmt.Sprint(testID)}
+	}
+	if err != nil {
+		return nil, v1Error(err, v1TestFields)
+	}
+
+	return u.test(), nil
+}
+
+func (tt *v1Tests) Update(t *Test) (*Test, error) {
+	t2 := *t
+	if t.TestID == 0 {
+		id, err := tt.client.v1Create("/uptime", v1TestValues(t))
+		if err != nil {
+			return nil, v1Error(err, v1TestFields)
+		}
+		t2.TestID = id
+		return &t2, nil
+	}
+
+	resp, err := tt.client.put(fmt.Sprintf("/uptime/%d", t.TestID), v1TestValues(t))
+	if err != nil {
+		return nil, v1Error(err, v1TestFields)
+	}
+	resp.Body.Close()
+
+	return &t2, nil
+}
+
+func (tt *v1Tests) Delete(testID int) error {
+	resp, err := tt.client.delete(fmt.Sprintf("/uptime/%d", testID), nil)
+	if err != nil {
+		return v1Error(err, v1TestFields)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// v1ContactGroup is a contact group of the v1 API.
+type v1ContactGroup struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	EmailAddresses []string `json:"email_addresses"`
+	MobileNumbers  []string `json:"mobile_numbers"`
+	PingURL        string   `json:"ping_url"`
+}
+
+func (g *v1ContactGroup) contactGroup() *ContactGroup {
+	id, _ := strconv.Atoi(g.ID)
+
+	return &ContactGroup{
+		ContactID: id,
+		GroupName: g.Name,
+		Emails:    g.EmailAddresses,
+		Mobiles:   strings.Join(g.MobileNumbers, ","),
+		PingURL:   g.PingURL,
+	}
+}
+
+// v1ContactGroupValues returns the form creating or updating cg. Boxcar,
+// Pushover and DesktopAlert have no v1 equivalent and are not sent.
+func v1ContactGroupValues(cg *ContactGroup) url.Values {
+	v := url.Values{
+		"name":     {cg.GroupName},
+		"ping_url": {cg.PingURL},
+	}
+	for _, email := range cg.Emails {
+		v.Add("email_addresses[]", email)
+	}
+	if cg.Mobiles != "" {
+		v["mobile_numbers[]"] = strings.Split(cg.Mobiles, ",")
+	}
+
+	return v
+}
+
+type v1ContactGroups struct {
+	client *Client
+}
+
+func (tt *v1ContactGroups) All() ([]*ContactGroup, error) {
+	var groups []*ContactGroup
+	err := tt.client.v1List("/contact-groups", nil, func(raw json.RawMessage) error {
+		var g v1ContactGroup
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return err
+		}
+		groups = append(groups, g.contactGroup())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting StatusCake contactGroups: %w", v1Error(err, v1ContactGroupFields))
+	}
+
+	return groups, nil
+}
+
+func (tt *v1ContactGroups) Detail(id int) (*ContactGroup, error) {
+	var g v1ContactGroup
+	err := tt.client.v1Detail(fmt.Sprintf("/contact-groups/%d", id), &g)
+	if _, ok := err.(*NotFoundError); ok {
+		return nil, &NotFoundError{Kind: "ContactGroup", ID: strconv.Itoa(id)}
+	}
+	if err != nil {
+		return nil, v1Error(err, v1ContactGroupFields)
+	}
+
+	return g.contactGroup(), nil
+}
+
+func (tt *v1ContactGroups) Update(cg *ContactGroup) (*ContactGroup, error) {
+	if cg.ContactID == 0 {
+		return tt.Create(cg)
+	}
+
+	resp, err := tt.client.put(fmt.Sprintf("/contact-groups/%d", cg.ContactID), v1ContactGroupValues(cg))
+	if err != nil {
+		return nil, fmt.Errorf("Error updating StatusCake ContactGroup: %w", v1Error(err, v1ContactGroupFields))
+	}
+	resp.Body.Close()
+
+	return cg, nil
+}
+
+func (tt *v1ContactGroups) Delete(id int) error {
+	resp, err := tt.client.delete(fmt.Sprintf("/contact-groups/%d", id), nil)
+	if err != nil {
+		return v1Error(err, v1ContactGroupFields)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (tt *v1ContactGroups) Create(cg *ContactGroup) (*ContactGroup, error) {
+	id, err := tt.client.v1Create("/contact-groups", v1ContactGroupValues(cg))
+	if err != nil {
+		return nil, fmt.Errorf("Error creating StatusCake ContactGroup: %w", v1Error(err, v1ContactGroupFields))
+	}
+	cg.ContactID = id
+
+	return cg, nil
+}
+
+type v1Locations struct {
+	client *Client
+}
+
+// All returns every uptime testing region, sorted by region code, which is
+// what the v1 API expects in place of a server code
+func (ll *v1Locations) All() ([]*Location, error) {
+	var locations []*Location
+	err := ll.client.v1List("/uptime-locations", nil, func(raw json.RawMessage) error {
+		var l struct {
+			Description string `json:"description"`
+			RegionCode  string `json:"region_code"`
+			IPv4        string `json:"ipv4"`
+			IPv6        string `json:"ipv6"`
+			Status      string `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return err
+		}
+		locations = append(locations, &Location{
+			ServerCode: l.RegionCode,
+			Title:      l.Description,
+			IP:         l.IPv4,
+			IPv6:       l.IPv6,
+			Status:     v1Status(l.Status),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting StatusCake locations: %w", v1Error(err, nil))
+	}
+	sortLocations(locations)
+
+	return locations, nil
+}
+
+// v1Account checks the token with the cheapest request the v1 API offers, as
+// it has no account endpoint. The account details are left empty.
+func (c *Client) v1Account() (*Account, error) {
+	resp, err := c.get("/uptime", url.Values{"limit": {"1"}})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting StatusCake account: %w", v1Error(err, nil))
+	}
+	resp.Body.Close()
+
+	return &Account{}, nil
+}
@@ -0,0 +1,155 @@
+package statuscake
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
+)
+
+func newV1TestClient(t *testing.T, srv *statuscaketest.Server, opts ...Option) *Client {
+	opts = append([]Option{WithAPIVersion(APIVersionV1), WithBaseURL(srv.URL + "/v1")}, opts...)
+	c, err := New(Auth{Token: statuscaketest.Token}, opts...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return c
+}
+
+func TestV1Tests(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+	c := newV1TestClient(t, srv)
+
+	// More tests than fit in one page
+	const count = v1PageSize + 5
+	for i := 0; i < count; i++ {
+		_, err := c.Tests().Update(&Test{
+			WebsiteName:   fmt.Sprintf("test-%d", i),
+			WebsiteURL:    "https://example.com",
+			TestType:      "HTTP",
+			CheckRate:     300,
+			TestTags:      []string{"team", fmt.Sprintf("n%d", i%2)},
+			NodeLocations: []string{"UK1"},
+			Paused:        i == 0,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	listed, err := c.Tests().AllListed()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(listed) != count {
+		t.Fatalf("expected %d tests over two pages, got %d", count, len(listed))
+	}
+	if n := srv.Requests("GET /v1/uptime"); n != 2 {
+		t.Errorf("expected two page requests, got %d", n)
+	}
+	first := listed[0]
+	if !first.Paused || first.Status != "Up" || !first.Keys["WebsiteName"] || first.Keys["LogoImage"] {
+		t.Errorf("unexpected listed test %+v with keys %v", first.Test, first.Keys)
+	}
+
+	filtered, err := c.Tests().AllWithFilter(url.Values{"tags": {"n1"}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(filtered) != count/2 {
+		t.Errorf("expected %d tests tagged n1, got %d", count/2, len(filtered))
+	}
+
+	test, err := c.Tests().Detail(first.TestID)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	test.CheckRate = 60
+	if _, err := c.Tests().Update(test); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	test, err = c.Tests().Detail(first.TestID)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if test.CheckRate != 60 || len(test.NodeLocations) != 1 || test.NodeLocations[0] != "UK1" {
+		t.Errorf("unexpected test after update: %+v", test)
+	}
+
+	if err := c.Tests().Delete(first.TestID); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, err = c.Tests().Detail(first.TestID)
+	if e, ok := err.(*NotFoundError); !ok || e.Kind != "Test" {
+		t.Errorf("expected a NotFoundError for the deleted test, got %T: %v", err, err)
+	}
+}
+
+func TestV1ContactGroups(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+	c := newV1TestClient(t, srv)
+
+	group, err := c.ContactGroups().Create(&ContactGroup{GroupName: "ops", Emails: []string{"a@example.com", "b@example.com"}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	group.GroupName = "oncall"
+	if _, err := c.ContactGroups().Update(group); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	all, err := c.ContactGroups().All()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(all) != 1 || all[0].GroupName != "oncall" || len(all[0].Emails) != 2 {
+		t.Errorf("unexpected contact groups %+v", all)
+	}
+
+	if err := c.ContactGroups().Delete(group.ContactID); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := c.ContactGroups().Detail(group.ContactID); err == nil {
+		t.Error("expected the deleted contact group to be gone")
+	}
+	if _, err := c.ContactGroups().Update(group); err == nil || !strings.HasPrefix(err.Error(), "Error updating StatusCake ContactGroup") {
+		t.Errorf("expected an error updating the deleted contact group, got %v", err)
+	}
+}
+
+func TestV1Errors(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	_, err := newV1TestClient(t, srv).Tests().Update(&Test{WebsiteURL: "https://example.com"})
+	var update *UpdateError
+	if !errors.As(err, &update) || update.Issues["WebsiteName"] == "" {
+		t.Errorf("expected an UpdateError naming WebsiteName, got %T: %v", err, err)
+	}
+
+	bad, err := New(Auth{Token: "wrong"}, WithAPIVersion(APIVersionV1), WithBaseURL(srv.URL+"/v1"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := bad.Account(); !errors.As(err, new(*AuthenticationError)) {
+		t.Errorf("expected an AuthenticationError, got %T: %v", err, err)
+	}
+	if _, err := newV1TestClient(t, srv).Account(); err != nil {
+		t.Errorf("expected the token to be accepted, got %s", err)
+	}
+
+	readOnly := newV1TestClient(t, srv, WithReadOnly(true))
+	if _, err := readOnly.Tests().Update(&Test{WebsiteName: "x"}); !errors.As(err, new(*ReadOnlyError)) {
+		t.Errorf("expected a ReadOnlyError, got %T: %v", err, err)
+	}
+
+	if _, err := New(Auth{Token: "token"}, WithAPIVersion("v2")); err == nil {
+		t.Error("expected an unknown API version to be refused")
+	}
+}
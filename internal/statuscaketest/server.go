@@ -0,0 +1,337 @@
+// Package statuscaketest provides an in-memory stand-in for the StatusCake
+// API, for tests that need to send real HTTP traffic through the client.
+//
+// It implements the endpoints the client uses, of the legacy API and, under
+// /v1, of the v1 REST API. Both share the same objects. It keeps its state
+// behind a mutex, and is consistent: every write is visible to the next read.
+package statuscaketest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials the server accepts
+const (
+	Username = "statuscaketest"
+	Apikey   = "statuscaketest-key"
+)
+
+// Server is a running stand-in StatusCake API.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	nextID        int
+	checks        int
+	tests         map[int]fields
+	contactGroups map[int]fields
+	requests      map[string]int
+}
+
+// fields holds the fields of a stored object as they were last written.
+type fields map[string]string
+
+// NewServer starts a stand-in server with a single testing node. Close it
+// when done.
+func NewServer() *Server {
+	s := &Server{
+		nextID:        1000,
+		tests:         make(map[int]fields),
+		contactGroups: make(map[int]fields),
+		requests:      make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Tests", s.handleTests)
+	mux.HandleFunc("/Tests/Details", s.handleTestDetails)
+	mux.HandleFunc("/Tests/Update", s.handleTestUpdate)
+	mux.HandleFunc("/ContactGroups", s.handleContactGroups)
+	mux.HandleFunc("/ContactGroups/Update", s.handleContactGroupUpdate)
+	mux.HandleFunc("/Locations/json", s.handleLocations)
+	mux.HandleFunc("/Auth", s.handleAuth)
+	s.handleV1(mux)
+	s.Server = httptest.NewServer(s.authenticate(mux))
+
+	return s
+}
+
+// Requests returns how many requests the server has answered for a method
+// and path, such as "GET /Tests".
+func (s *Server) Requests(methodAndPath string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[methodAndPath]
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.Method+" "+r.URL.Path]++
+		s.mu.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/v1/") {
+			if r.Header.Get("Authorization") != "Bearer "+Token {
+				writeV1Error(w, http.StatusUnauthorized, "Unauthenticated", nil)
+				return
+			}
+		} else if r.Header.Get("Username") != Username || r.Header.Get("API") != Apikey {
+			writeJSON(w, map[string]interface{}{"ErrNo": 0, "Error": "Authentication Failed"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"Success": true,
+		"Details": map[string]interface{}{
+			"Username": Username,
+			"Email":    Username + "@example.com",
+			"Plan":     "SUPERIOR",
+			"MaxTests": 100,
+		},
+	})
+}
+
+func (s *Server) handleTests(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag := r.URL.Query().Get("tags")
+	list := []interface{}{}
+	for _, id := range sortedIDs(s.tests) {
+		t := s.tests[id]
+		if tag != "" && !containsAll(splitList(t["TestTags"]), splitList(tag)) {
+			continue
+		}
+		// The fields of the real list: the others need Details
+		list = append(list, map[string]interface{}{
+			"TestID":        id,
+			"Paused":        t["Paused"] == "1",
+			"TestType":      t["TestType"],
+			"WebsiteName":   t["WebsiteName"],
+			"WebsiteURL":    t["WebsiteURL"],
+			"ContactGroup":  splitList(t["ContactGroup"]),
+			"ContactID":     atoi(t["ContactID"]),
+			"Status":        "Up",
+			"Uptime":        100,
+			"CheckRate":     atoi(t["CheckRate"]),
+			"Public":        0,
+			"TestTags":      splitList(t["TestTags"]),
+			"NodeLocations": splitList(t["NodeLocations"]),
+			"Confirmation":  strconv.Itoa(atoi(t["Confirmation"])),
+			"TriggerRate":   atoi(t["TriggerRate"]),
+			"StatusCode":    "200",
+		})
+	}
+
+	writeJSON(w, list)
+}
+
+func (s *Server) handleTestDetails(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := atoi(r.URL.Query().Get("TestID"))
+	t, ok := s.tests[id]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		groups := []interface{}{}
+		for _, g := range splitList(t["ContactGroup"]) {
+			groups = append(groups, map[string]interface{}{"ID": atoi(g)})
+		}
+		writeJSON(w, map[string]interface{}{
+			"TestID":        id,
+			"TestType":      t["TestType"],
+			"Paused":        t["Paused"] == "1",
+			"WebsiteName":   t["WebsiteName"],
+			"URI":           t["WebsiteURL"],
+			"ContactGroups": groups,
+			"Status":        "Up",
+			"Uptime":        100,
+			"CheckRate":     atoi(t["CheckRate"]),
+			"Timeout":       atoi(t["Timeout"]),
+			"Confirmation":  strconv.Itoa(atoi(t["Confirmation"])),
+			"TriggerRate":   strconv.Itoa(atoi(t["TriggerRate"])),
+			"DownTimes":     "0",
+			"NodeLocations": splitList(t["NodeLocations"]),
+			"StatusCodes":   splitList(t["StatusCodes"]),
+			"Tags":          splitList(t["TestTags"]),
+			"StatusCode":    200,
+			"LastTested":    t["LastTested"],
+		})
+	case http.MethodDelete:
+		if !ok {
+			writeJSON(w, map[string]interface{}{"Success": false, "Error": "No test found"})
+			return
+		}
+		delete(s.tests, id)
+		writeJSON(w, map[string]interface{}{"Success": true})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleTestUpdate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := atoi(r.PostForm.Get("TestID"))
+	if id == 0 {
+		s.nextID++
+		id = s.nextID
+	} else if _, ok := s.tests[id]; !ok {
+		writeJSON(w, map[string]interface{}{"Success": false, "Message": "No test found", "Issues": map[string]string{}})
+		return
+	}
+	if rate := atoi(r.PostForm.Get("CheckRate")); rate < 0 || rate > 24000 {
+		writeJSON(w, map[string]interface{}{"Success": false, "Message": "Validation failed", "Issues": map[string]string{"CheckRate": "must be between 0 and 24000"}})
+		return
+	}
+
+	t := fields{}
+	for k := range r.PostForm {
+		t[k] = r.PostForm.Get(k)
+	}
+	s.tests[id] = t
+	s.check(t)
+
+	writeJSON(w, map[string]interface{}{"Success": true, "Message": "", "Issues": map[string]string{}, "InsertID": id})
+}
+
+func (s *Server) handleContactGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := []interface{}{}
+	for _, id := range sortedIDs(s.contactGroups) {
+		g := s.contactGroups[id]
+		list = append(list, map[string]interface{}{
+			"ContactID": id,
+			"GroupName": g["GroupName"],
+			"Emails":    splitList(g["Email"]),
+			"PingURL":   g["PingURL"],
+		})
+	}
+
+	writeJSON(w, list)
+}
+
+func (s *Server) handleContactGroupUpdate(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := atoi(r.PostForm.Get("ContactID"))
+		if id == 0 {
+			s.nextID++
+			id = s.nextID
+		} else if _, ok := s.contactGroups[id]; !ok {
+			writeJSON(w, map[string]interface{}{"Success": false, "Message": "No contact group found"})
+			return
+		}
+
+		g := fields{}
+		for k := range r.PostForm {
+			g[k] = r.PostForm.Get(k)
+		}
+		s.contactGroups[id] = g
+
+		writeJSON(w, map[string]interface{}{"Success": true, "Message": "", "InsertID": id})
+	case http.MethodDelete:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.contactGroups, atoi(r.URL.Query().Get("ContactID")))
+		writeJSON(w, map[string]interface{}{"Success": true})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleLocations(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"1": map[string]string{
+			"guid":       "1",
+			"servercode": "UK1",
+			"title":      "London, United Kingdom",
+			"countryiso": "GB",
+			"status":     "Up",
+		},
+	})
+}
+
+// check runs the check of a test that was just written, which records when
+// it was last tested. Check times are a minute apart.
+func (s *Server) check(t fields) {
+	s.checks++
+	t["LastTested"] = time.Date(2019, 10, 19, 10, s.checks, 0, 0, time.UTC).Format("2006-01-02 15:04:05")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func sortedIDs(m map[int]fields) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func splitList(v string) []string {
+	if v == "" {
+		return []string{}
+	}
+
+	return strings.Split(v, ",")
+}
+
+func containsAll(have, want []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, v := range have {
+		set[v] = true
+	}
+	for _, v := range want {
+		if !set[v] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func atoi(v string) int {
+	i, _ := strconv.Atoi(v)
+	return i
+}
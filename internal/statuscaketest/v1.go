@@ -0,0 +1,307 @@
+package statuscaketest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Token is the bearer token the v1 endpoints accept
+const Token = "statuscaketest-token"
+
+// v1TestForm maps the v1 form fields of an uptime check to the legacy fields
+// tests are stored with, so both APIs share the same tests.
+var v1TestForm = map[string]string{
+	"name":               "WebsiteName",
+	"website_url":        "WebsiteURL",
+	"test_type":          "TestType",
+	"check_rate":         "CheckRate",
+	"contact_groups_csv": "ContactGroup",
+	"paused":             "Paused",
+	"timeout":            "Timeout",
+	"confirmation":       "Confirmation",
+	"trigger_rate":       "TriggerRate",
+	"tags_csv":           "TestTags",
+	"regions_csv":        "NodeLocations",
+	"status_codes_csv":   "StatusCodes",
+	"custom_header":      "CustomHeader",
+	"user_agent":         "UserAgent",
+	"find_string":        "FindString",
+	"host":               "WebsiteHost",
+	"port":               "Port",
+}
+
+// v1ContactGroupForm does the same for contact groups.
+var v1ContactGroupForm = map[string]string{
+	"name":              "GroupName",
+	"email_addresses[]": "Email",
+	"mobile_numbers[]":  "Mobile",
+	"ping_url":          "PingURL",
+}
+
+func (s *Server) handleV1(mux *http.ServeMux) {
+	mux.HandleFunc("/v1/uptime", s.handleV1Uptimes)
+	mux.HandleFunc("/v1/uptime/", s.handleV1Uptime)
+	mux.HandleFunc("/v1/contact-groups", s.handleV1ContactGroups)
+	mux.HandleFunc("/v1/contact-groups/", s.handleV1ContactGroup)
+	mux.HandleFunc("/v1/uptime-locations", s.handleV1Locations)
+}
+
+func (s *Server) handleV1Uptimes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		tags := splitList(r.URL.Query().Get("tags"))
+		var list []interface{}
+		for _, id := range sortedIDs(s.tests) {
+			t := s.tests[id]
+			if !containsAll(splitList(t["TestTags"]), tags) {
+				continue
+			}
+			list = append(list, map[string]interface{}{
+				"id":             strconv.Itoa(id),
+				"name":           t["WebsiteName"],
+				"website_url":    t["WebsiteURL"],
+				"test_type":      t["TestType"],
+				"check_rate":     atoi(t["CheckRate"]),
+				"contact_groups": splitList(t["ContactGroup"]),
+				"paused":         t["Paused"] == "1",
+				"status":         "up",
+				"uptime":         100,
+				"tags":           splitList(t["TestTags"]),
+			})
+		}
+		writeV1Page(w, r, list)
+	case http.MethodPost:
+		t, ok := v1Fields(w, r, v1TestForm)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.nextID++
+		s.tests[s.nextID] = t
+		s.check(t)
+		writeCreated(w, map[string]interface{}{"data": map[string]string{"new_id": strconv.Itoa(s.nextID)}})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleV1Uptime(w http.ResponseWriter, r *http.Request) {
+	id := atoi(strings.TrimPrefix(r.URL.Path, "/v1/uptime/"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tests[id]
+	if !ok {
+		writeV1Error(w, http.StatusNotFound, "No uptime check found", nil)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		servers := []interface{}{}
+		for _, region := range splitList(t["NodeLocations"]) {
+			servers = append(servers, map[string]string{"region_code": region})
+		}
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+			"id":             strconv.Itoa(id),
+			"name":           t["WebsiteName"],
+			"website_url":    t["WebsiteURL"],
+			"test_type":      t["TestType"],
+			"check_rate":     atoi(t["CheckRate"]),
+			"contact_groups": splitList(t["ContactGroup"]),
+			"paused":         t["Paused"] == "1",
+			"status":         "up",
+			"uptime":         100,
+			"tags":           splitList(t["TestTags"]),
+			"timeout":        atoi(t["Timeout"]),
+			"confirmation":   atoi(t["Confirmation"]),
+			"trigger_rate":   atoi(t["TriggerRate"]),
+			"servers":        servers,
+			"status_codes":   splitList(t["StatusCodes"]),
+			"custom_header":  t["CustomHeader"],
+			"user_agent":     t["UserAgent"],
+			"find_string":    t["FindString"],
+			"host":           t["WebsiteHost"],
+			"port":           atoi(t["Port"]),
+			"last_tested_at": t["LastTested"],
+		}})
+	case http.MethodPut:
+		update, ok := v1Fields(w, r, v1TestForm)
+		if !ok {
+			return
+		}
+
+		for k, v := range update {
+			t[k] = v
+		}
+		s.check(t)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.tests, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleV1ContactGroups(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var list []interface{}
+		for _, id := range sortedIDs(s.contactGroups) {
+			list = append(list, v1ContactGroup(id, s.contactGroups[id]))
+		}
+		writeV1Page(w, r, list)
+	case http.MethodPost:
+		g, ok := v1Fields(w, r, v1ContactGroupForm)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.nextID++
+		s.contactGroups[s.nextID] = g
+		writeCreated(w, map[string]interface{}{"data": map[string]string{"new_id": strconv.Itoa(s.nextID)}})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleV1ContactGroup(w http.ResponseWriter, r *http.Request) {
+	id := atoi(strings.TrimPrefix(r.URL.Path, "/v1/contact-groups/"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.contactGroups[id]
+	if !ok {
+		writeV1Error(w, http.StatusNotFound, "No contact group found", nil)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, map[string]interface{}{"data": v1ContactGroup(id, g)})
+	case http.MethodPut:
+		update, ok := v1Fields(w, r, v1ContactGroupForm)
+		if !ok {
+			return
+		}
+
+		s.contactGroups[id] = update
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.contactGroups, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleV1Locations(w http.ResponseWriter, r *http.Request) {
+	writeV1Page(w, r, []interface{}{
+		map[string]string{
+			"description": "London, United Kingdom",
+			"region_code": "UK1",
+			"ipv4":        "127.0.0.1",
+			"status":      "up",
+		},
+	})
+}
+
+func v1ContactGroup(id int, g fields) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              strconv.Itoa(id),
+		"name":            g["GroupName"],
+		"email_addresses": splitList(g["Email"]),
+		"mobile_numbers":  splitList(g["Mobile"]),
+		"ping_url":        g["PingURL"],
+	}
+}
+
+// v1Fields reads a v1 form into the legacy fields it is stored as. Lists
+// sent as repeated fields are stored comma separated, like legacy lists.
+func v1Fields(w http.ResponseWriter, r *http.Request, form map[string]string) (fields, bool) {
+	if err := r.ParseForm(); err != nil {
+		writeV1Error(w, http.StatusBadRequest, err.Error(), nil)
+		return nil, false
+	}
+
+	f := fields{}
+	for k, values := range r.PostForm {
+		legacy, ok := form[k]
+		if !ok {
+			continue
+		}
+		v := strings.Join(values, ",")
+		if k == "paused" {
+			v = map[bool]string{true: "1", false: "0"}[v == "true"]
+		}
+		f[legacy] = v
+	}
+
+	if r.Method == http.MethodPost && r.PostForm.Get("name") == "" {
+		writeV1Error(w, http.StatusBadRequest, "The provided parameters are invalid", map[string][]string{
+			"name": {"The name field is required."},
+		})
+		return nil, false
+	}
+
+	return f, true
+}
+
+// writeV1Page writes the page of list the page and limit parameters ask for.
+func writeV1Page(w http.ResponseWriter, r *http.Request, list []interface{}) {
+	page, limit := atoi(r.URL.Query().Get("page")), atoi(r.URL.Query().Get("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 25
+	}
+
+	pageCount := (len(list) + limit - 1) / limit
+	start, end := (page-1)*limit, page*limit
+	if start > len(list) {
+		start = len(list)
+	}
+	if end > len(list) {
+		end = len(list)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"data": append([]interface{}{}, list[start:end]...),
+		"metadata": map[string]int{
+			"page":        page,
+			"per_page":    limit,
+			"page_count":  pageCount,
+			"total_count": len(list),
+		},
+	})
+}
+
+func writeCreated(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeV1Error(w http.ResponseWriter, status int, message string, errors map[string][]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": message, "errors": errors})
+}
@@ -0,0 +1,63 @@
+package statuscake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+// v1UnsupportedTestAttributes are the statuscake_test arguments the v1 API
+// has no equivalent for. The v1 locations have no country, so
+// location_selector cannot match any of them.
+var v1UnsupportedTestAttributes = []string{
+	"ping_url", "public", "logo_image", "branding", "virus", "real_browser", "location_selector",
+}
+
+// v1UnsupportedContactGroupAttributes are the statuscake_contact_group
+// arguments the v1 API has no equivalent for.
+var v1UnsupportedContactGroupAttributes = []string{
+	"desktop_alert", "pushover", "boxcar",
+}
+
+// apiVersion returns the API version to use: the one configured, or v1 when
+// the credentials are a bearer token. Only v1 accepts bearer tokens.
+func apiVersion(configured string, auth statuscake.Auth) (string, error) {
+	switch {
+	case configured == "" && auth.Token != "":
+		return statuscake.APIVersionV1, nil
+	case configured == "":
+		return statuscake.APIVersionLegacy, nil
+	case configured == statuscake.APIVersionV1 && auth.Token == "":
+		return "", fmt.Errorf("api_version %q needs api_token, or a credential_process printing a token", configured)
+	case configured == statuscake.APIVersionLegacy && auth.Token != "":
+		return "", fmt.Errorf("api_version %q needs username and apikey, not a token", configured)
+	}
+
+	return configured, nil
+}
+
+// checkAPISupport fails when any of the attributes the API in use cannot
+// store is set, rather than dropping it and showing a diff on every plan.
+func checkAPISupport(client *StatusCakeClient, d *schema.ResourceData, unsupported []string) error {
+	if client.APIVersion() != statuscake.APIVersionV1 {
+		return nil
+	}
+
+	var set []string
+	for _, attr := range unsupported {
+		if _, ok := d.GetOk(attr); ok {
+			set = append(set, attr)
+		}
+	}
+	if len(set) > 0 {
+		return unsupportedError(set)
+	}
+
+	return nil
+}
+
+func unsupportedError(attrs []string) error {
+	return fmt.Errorf("the StatusCake v1 API does not support %s: unset them or use api_version \"legacy\"", strings.Join(attrs, ", "))
+}
@@ -0,0 +1,95 @@
+package statuscake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func TestAPIVersion(t *testing.T) {
+	keys := statuscake.Auth{Username: "user", Apikey: "key"}
+	token := statuscake.Auth{Token: "token"}
+
+	cases := []struct {
+		configured string
+		auth       statuscake.Auth
+		expected   string
+		err        bool
+	}{
+		{"", keys, statuscake.APIVersionLegacy, false},
+		{"", token, statuscake.APIVersionV1, false},
+		{statuscake.APIVersionLegacy, keys, statuscake.APIVersionLegacy, false},
+		{statuscake.APIVersionV1, token, statuscake.APIVersionV1, false},
+		{statuscake.APIVersionV1, keys, "", true},
+		{statuscake.APIVersionLegacy, token, "", true},
+	}
+
+	for _, tc := range cases {
+		version, err := apiVersion(tc.configured, tc.auth)
+		if (err != nil) != tc.err {
+			t.Errorf("%q with %+v: unexpected error %v", tc.configured, tc.auth, err)
+		}
+		if version != tc.expected {
+			t.Errorf("%q with %+v: expected %q, got %q", tc.configured, tc.auth, tc.expected, version)
+		}
+	}
+}
+
+func TestCheckAPISupport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, map[string]interface{}{
+		"website_name": "example",
+		"website_url":  "https://example.com",
+		"test_type":    "HTTP",
+		"check_rate":   300,
+		"virus":        1,
+		"ping_url":     "https://example.com/ping",
+	})
+
+	legacy, err := statuscake.New(statuscake.Auth{Username: "user", Apikey: "key"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := checkAPISupport(&StatusCakeClient{Client: legacy}, d, v1UnsupportedTestAttributes); err != nil {
+		t.Errorf("expected the legacy API to support every attribute, got %s", err)
+	}
+
+	v1, err := statuscake.New(statuscake.Auth{Token: "token"}, statuscake.WithAPIVersion(statuscake.APIVersionV1))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = checkAPISupport(&StatusCakeClient{Client: v1}, d, v1UnsupportedTestAttributes)
+	if err == nil || !strings.Contains(err.Error(), "ping_url, virus") {
+		t.Errorf("expected an error naming ping_url and virus, got %v", err)
+	}
+
+	selector := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, map[string]interface{}{
+		"website_name":      "example",
+		"website_url":       "https://example.com",
+		"test_type":         "HTTP",
+		"location_selector": []interface{}{map[string]interface{}{"regions": []interface{}{"europe"}}},
+	})
+	err = checkAPISupport(&StatusCakeClient{Client: v1}, selector, v1UnsupportedTestAttributes)
+	if err == nil || !strings.Contains(err.Error(), "location_selector") {
+		t.Errorf("expected an error naming location_selector, got %v", err)
+	}
+
+	// The plan fails before trying to match the v1 locations
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"website_name":      "example",
+		"website_url":       "https://example.com",
+		"test_type":         "HTTP",
+		"location_selector": []interface{}{map[string]interface{}{"regions": []interface{}{"europe"}}},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	meta := &StatusCakeClient{Client: v1, providerState: &providerState{}}
+	_, err = resourceStatusCakeTest().Diff(nil, terraform.NewResourceConfig(raw), meta)
+	if err == nil || !strings.Contains(err.Error(), "does not support location_selector") {
+		t.Errorf("expected the plan to fail naming location_selector, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package statuscake
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+// StatusCakeClient is the meta value handed to every resource. It wraps the
+// API client together with lookups shared by the whole provider instance.
+type StatusCakeClient struct {
+	*statuscake.Client
+	*providerState
+}
+
+// providerState is the part of a StatusCakeClient shared by the copies made
+// with withContext.
+type providerState struct {
+	// defaultTags are merged into the tags of every resource that has them.
+	defaultTags []string
+
+	// testDefaults supply statuscake_test attributes left unset.
+	testDefaults testDefaults
+
+	// readOnly makes every create, update and delete fail before any request.
+	readOnly bool
+
+	// maxDeletes is the max_deletes_per_run budget tracked by deletes.
+	maxDeletes int
+	deletes    *deleteLedger
+
+	mu            sync.Mutex
+	contactGroups map[int]*statuscake.ContactGroup
+	locations     []*statuscake.Location
+
+	// listedTests is the account's test list, loaded once so a refresh does
+	// not need one Detail request per test. listedTestsErr stops a failing
+	// list from being requested again for every test.
+	listedTests    map[int]*statuscake.ListedTest
+	listedTestsErr error
+}
+
+// withContext returns a copy of the client whose API requests run under ctx,
+// sharing its caches and settings.
+func (c *StatusCakeClient) withContext(ctx context.Context) *StatusCakeClient {
+	return &StatusCakeClient{
+		Client:        c.Client.WithContext(ctx),
+		providerState: c.providerState,
+	}
+}
+
+// contactGroupList returns every contact group in the account by ID. The
+// list is fetched once and shared by every contact group read and reference
+// check until refresh is set or it is invalidated.
+func (c *StatusCakeClient) contactGroupList(refresh bool) (map[int]*statuscake.ContactGroup, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.contactGroups != nil && !refresh {
+		return c.contactGroups, nil
+	}
+
+	groups, err := c.ContactGroups().All()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*statuscake.ContactGroup, len(groups))
+	for _, g := range groups {
+		byID[g.ContactID] = g
+	}
+	c.contactGroups = byID
+
+	return byID, nil
+}
+
+// contactGroup returns one contact group from the cached list. A miss fetches
+// the list again once, as the group may have been created since.
+func (c *StatusCakeClient) contactGroup(id int, refresh bool) (*statuscake.ContactGroup, error) {
+	groups, err := c.contactGroupList(refresh)
+	if err != nil {
+		return nil, err
+	}
+	if g, ok := groups[id]; ok {
+		return g, nil
+	}
+	if !refresh {
+		return c.contactGroup(id, true)
+	}
+
+	return nil, &statuscake.NotFoundError{Kind: "ContactGroup", ID: strconv.Itoa(id)}
+}
+
+// contactGroupIDs returns the IDs of every contact group in the account.
+func (c *StatusCakeClient) contactGroupIDs(refresh bool) (map[string]bool, error) {
+	groups, err := c.contactGroupList(refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool, len(groups))
+	for id := range groups {
+		ids[strconv.Itoa(id)] = true
+	}
+
+	return ids, nil
+}
+
+// invalidateContactGroups drops the cached contact group list after a write.
+func (c *StatusCakeClient) invalidateContactGroups() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.contactGroups = nil
+}
+
+// listedTest returns a test from the account's test list, which is fetched
+// once per provider instance. ok is false when the test is not in the list or
+// has been written since the list was fetched.
+func (c *StatusCakeClient) listedTest(id int) (test *statuscake.ListedTest, ok bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.listedTests == nil {
+		if c.listedTestsErr != nil {
+			return nil, false, c.listedTestsErr
+		}
+
+		listed, err := c.Tests().AllListed()
+		if err != nil {
+			c.listedTestsErr = err
+			return nil, false, err
+		}
+
+		c.listedTests = make(map[int]*statuscake.ListedTest, len(listed))
+		for _, t := range listed {
+			c.listedTests[t.TestID] = t
+		}
+	}
+
+	test, ok = c.listedTests[id]
+	return test, ok, nil
+}
+
+// invalidateTest drops a test from the cached test list after it is written,
+// so it is read with Detail from then on.
+func (c *StatusCakeClient) invalidateTest(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.listedTests, id)
+}
+
+// nodeLocations returns every StatusCake testing node, fetched once per
+// provider instance unless refresh is set.
+func (c *StatusCakeClient) nodeLocations(refresh bool) ([]*statuscake.Location, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.locations != nil && !refresh {
+		return c.locations, nil
+	}
+
+	locations, err := c.Locations().All()
+	if err != nil {
+		return nil, err
+	}
+	c.locations = locations
+
+	return locations, nil
+}
+
+// nodeLocationCodes returns the server codes of every testing node.
+func (c *StatusCakeClient) nodeLocationCodes(refresh bool) (map[string]bool, error) {
+	locations, err := c.nodeLocations(refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make(map[string]bool, len(locations))
+	for _, l := range locations {
+		codes[l.ServerCode] = true
+	}
+
+	return codes, nil
+}
+
+// missingReferences returns the refs that are not in the set returned by
+// known. A miss triggers one refresh, since the cached set may predate an
+// object created earlier in the same run.
+func missingReferences(refs []string, known func(refresh bool) (map[string]bool, error)) ([]string, error) {
+	missing, err := filterKnown(refs, known, false)
+	if err != nil || len(missing) == 0 {
+		return missing, err
+	}
+
+	return filterKnown(refs, known, true)
+}
+
+func filterKnown(refs []string, known func(refresh bool) (map[string]bool, error), refresh bool) ([]string, error) {
+	set, err := known(refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, ref := range refs {
+		if !set[ref] {
+			missing = append(missing, ref)
+		}
+	}
+
+	return missing, nil
+}
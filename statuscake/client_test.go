@@ -0,0 +1,85 @@
+package statuscake
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func TestMissingReferences(t *testing.T) {
+	calls := 0
+	known := func(refresh bool) (map[string]bool, error) {
+		calls++
+		if refresh {
+			return map[string]bool{"1": true, "2": true}, nil
+		}
+		return map[string]bool{"1": true}, nil
+	}
+
+	missing, err := missingReferences([]string{"1"}, known)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(missing) != 0 || calls != 1 {
+		t.Fatalf("expected a single cached lookup with no misses, got %v after %d calls", missing, calls)
+	}
+
+	calls = 0
+	missing, err = missingReferences([]string{"1", "2", "3"}, known)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"3"}) {
+		t.Fatalf("expected only 3 to be missing after a refresh, got %v", missing)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a miss to trigger exactly one refresh, got %d calls", calls)
+	}
+}
+
+func TestListedTest_invalidate(t *testing.T) {
+	client := &StatusCakeClient{providerState: &providerState{
+		listedTests: map[int]*statuscake.ListedTest{
+			1: {Test: &statuscake.Test{TestID: 1}},
+			2: {Test: &statuscake.Test{TestID: 2}},
+		},
+	}}
+
+	if _, ok, err := client.listedTest(1); err != nil || !ok {
+		t.Fatalf("expected test 1 to be listed, got %t, %v", ok, err)
+	}
+
+	client.invalidateTest(1)
+	if _, ok, err := client.listedTest(1); err != nil || ok {
+		t.Fatalf("expected test 1 to be dropped after a write, got %t, %v", ok, err)
+	}
+	if _, ok, _ := client.listedTest(2); !ok {
+		t.Fatal("expected test 2 to stay listed")
+	}
+}
+
+func TestContactGroup_cached(t *testing.T) {
+	client := &StatusCakeClient{providerState: &providerState{
+		contactGroups: map[int]*statuscake.ContactGroup{
+			1: {ContactID: 1, GroupName: "ops"},
+			2: {ContactID: 2, GroupName: "dev"},
+		},
+	}}
+
+	g, err := client.contactGroup(2, false)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if g.GroupName != "dev" {
+		t.Fatalf("expected contact group dev, got %q", g.GroupName)
+	}
+
+	ids, err := client.contactGroupIDs(false)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(ids, map[string]bool{"1": true, "2": true}) {
+		t.Fatalf("unexpected contact group IDs: %v", ids)
+	}
+}
@@ -0,0 +1,143 @@
+package statuscake
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+// Default operation timeouts. Create, update and delete cover waiting for the
+// eventually consistent API to reflect a write, read covers retrying a
+// failing read.
+const (
+	defaultWriteTimeout = 5 * time.Minute
+	defaultReadTimeout  = 1 * time.Minute
+)
+
+func defaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultWriteTimeout),
+		Update: schema.DefaultTimeout(defaultWriteTimeout),
+		Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		Read:   schema.DefaultTimeout(defaultReadTimeout),
+	}
+}
+
+// retryRead calls read until it returns no error and reports no stale value,
+// or until timeout passes. read returns a description of the first value
+// that does not match what was written, or "" once the API is up to date.
+// Only errors that may go away are retried: not found, as a freshly created
+// object may not be found yet, network and server errors, and rate limiting,
+// after waiting as long as the API asked. Any other error is returned at once.
+func retryRead(client *StatusCakeClient, timeout time.Duration, what string, read func() (string, error)) error {
+	attempt := 0
+	return resource.Retry(timeout, func() *resource.RetryError {
+		if attempt++; attempt > 1 {
+			countRetry(client)
+		}
+
+		stale, err := read()
+		if isInterrupted(err) {
+			return resource.NonRetryableError(err)
+		}
+		if err != nil {
+			err = fmt.Errorf("Error reading %s: %w", what, err)
+			var rateLimited *statuscake.RateLimitError
+			switch {
+			case errors.As(err, &rateLimited):
+				time.Sleep(rateLimited.RetryAfter)
+				return resource.RetryableError(err)
+			case isNotFound(err), isTransient(err):
+				return resource.RetryableError(err)
+			default:
+				return resource.NonRetryableError(err)
+			}
+		}
+		if stale != "" {
+			return resource.RetryableError(fmt.Errorf("%s does not reflect the last write yet: %s", what, stale))
+		}
+		return nil
+	})
+}
+
+// isTransient reports whether err may go away on its own: a network error,
+// a request that timed out or a server error. The API may have applied the
+// request before it failed.
+func isTransient(err error) bool {
+	var canceled *statuscake.CanceledError
+	if errors.As(err, &canceled) {
+		return canceled.Timeout > 0
+	}
+	var httpErr *statuscake.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isInterrupted reports whether err comes from a request cancelled because
+// Terraform is stopping, which retrying cannot fix. Requests that timed out
+// are not interrupted.
+func isInterrupted(err error) bool {
+	var canceled *statuscake.CanceledError
+	return errors.As(err, &canceled) && canceled.Timeout == 0
+}
+
+// staleTestField returns the first field of got that does not match the test
+// just written, or "". Only fields the API echoes back unchanged are compared.
+func staleTestField(want, got *statuscake.Test) string {
+	switch {
+	case got.TestID != want.TestID:
+		return fmt.Sprintf("TestID is %d, expected %d", got.TestID, want.TestID)
+	case got.WebsiteName != want.WebsiteName:
+		return fmt.Sprintf("WebsiteName is %q, expected %q", got.WebsiteName, want.WebsiteName)
+	case got.CheckRate != want.CheckRate:
+		return fmt.Sprintf("CheckRate is %d, expected %d", got.CheckRate, want.CheckRate)
+	case got.Paused != want.Paused:
+		return fmt.Sprintf("Paused is %t, expected %t", got.Paused, want.Paused)
+	case !sameStrings(got.TestTags, want.TestTags):
+		return fmt.Sprintf("TestTags are %v, expected %v", got.TestTags, want.TestTags)
+	}
+
+	return ""
+}
+
+// staleContactGroupField returns the first field of got that does not match
+// the contact group just written, or "".
+func staleContactGroupField(want, got *statuscake.ContactGroup) string {
+	switch {
+	case got.ContactID != want.ContactID:
+		return fmt.Sprintf("ContactID is %d, expected %d", got.ContactID, want.ContactID)
+	case got.GroupName != want.GroupName:
+		return fmt.Sprintf("GroupName is %q, expected %q", got.GroupName, want.GroupName)
+	case !sameStrings(got.Emails, want.Emails):
+		return fmt.Sprintf("Emails are %v, expected %v", got.Emails, want.Emails)
+	}
+
+	return ""
+}
+
+// sameStrings compares two lists as sets, ignoring blanks.
+func sameStrings(a, b []string) bool {
+	normalize := func(in []string) string {
+		out := make([]string, 0, len(in))
+		for _, v := range in {
+			if v != "" {
+				out = append(out, v)
+			}
+		}
+		sort.Strings(out)
+		return strings.Join(out, "\x00")
+	}
+
+	return normalize(a) == normalize(b)
+}
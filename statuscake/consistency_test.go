@@ -0,0 +1,129 @@
+package statuscake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func TestStaleTestField(t *testing.T) {
+	written := &statuscake.Test{
+		TestID:      1,
+		WebsiteName: "api",
+		CheckRate:   300,
+		TestTags:    []string{"b", "a"},
+	}
+
+	cases := []struct {
+		got   statuscake.Test
+		stale bool
+	}{
+		{statuscake.Test{TestID: 1, WebsiteName: "api", CheckRate: 300, TestTags: []string{"a", "b"}}, false},
+		{statuscake.Test{TestID: 1, WebsiteName: "api", CheckRate: 300, TestTags: []string{"a", "b", ""}}, false},
+		{statuscake.Test{TestID: 0, WebsiteName: "api", CheckRate: 300, TestTags: []string{"a", "b"}}, true},
+		{statuscake.Test{TestID: 1, WebsiteName: "old", CheckRate: 300, TestTags: []string{"a", "b"}}, true},
+		{statuscake.Test{TestID: 1, WebsiteName: "api", CheckRate: 60, TestTags: []string{"a", "b"}}, true},
+		{statuscake.Test{TestID: 1, WebsiteName: "api", CheckRate: 300, TestTags: []string{"a", "b"}, Paused: true}, true},
+		{statuscake.Test{TestID: 1, WebsiteName: "api", CheckRate: 300, TestTags: []string{"a"}}, true},
+	}
+
+	for i, tc := range cases {
+		stale := staleTestField(written, &tc.got)
+		if (stale != "") != tc.stale {
+			t.Errorf("case %d: expected stale %t, got %q", i, tc.stale, stale)
+		}
+	}
+}
+
+func TestStaleContactGroupField(t *testing.T) {
+	written := &statuscake.ContactGroup{ContactID: 5, GroupName: "ops", Emails: []string{"a@example.com"}}
+
+	if stale := staleContactGroupField(written, &statuscake.ContactGroup{ContactID: 5, GroupName: "ops", Emails: []string{"a@example.com"}}); stale != "" {
+		t.Errorf("expected no stale field, got %q", stale)
+	}
+	if stale := staleContactGroupField(written, &statuscake.ContactGroup{ContactID: 5, GroupName: "ops"}); stale == "" {
+		t.Error("expected missing emails to be stale")
+	}
+}
+
+func TestIsInterrupted(t *testing.T) {
+	cases := []struct {
+		err         error
+		interrupted bool
+	}{
+		{nil, false},
+		{fmt.Errorf("HTTP error: 500"), false},
+		{&statuscake.CanceledError{Method: "GET", Path: "/Tests", Err: context.Canceled}, true},
+		{fmt.Errorf("Error getting StatusCake contactGroups: %w", &statuscake.CanceledError{Err: context.Canceled}), true},
+		{&statuscake.CanceledError{Method: "GET", Path: "/Tests", Err: context.DeadlineExceeded, Timeout: time.Minute}, false},
+	}
+
+	for i, tc := range cases {
+		if got := isInterrupted(tc.err); got != tc.interrupted {
+			t.Errorf("case %d: expected %t, got %t for %v", i, tc.interrupted, got, tc.err)
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		err       error
+		transient bool
+	}{
+		{nil, false},
+		{&url.Error{Op: "Post", URL: "https://app.statuscake.com/API/Tests/Update", Err: io.EOF}, true},
+		{&statuscake.CanceledError{Method: "PUT", Path: "/Tests/Update", Err: context.DeadlineExceeded, Timeout: time.Minute}, true},
+		{&statuscake.CanceledError{Method: "PUT", Path: "/Tests/Update", Err: context.Canceled}, false},
+		{&statuscake.HTTPError{StatusCode: 502}, true},
+		{&statuscake.HTTPError{StatusCode: 400}, false},
+		{&statuscake.UpdateError{Message: "Validation failed", Issues: statuscake.ValidationError{"CheckRate": "too high"}}, false},
+		{statuscake.ValidationError{"WebsiteURL": "required"}, false},
+		{&statuscake.AuthenticationError{ErrNo: 0, Message: "bad key"}, false},
+	}
+
+	for i, tc := range cases {
+		if got := isTransient(tc.err); got != tc.transient {
+			t.Errorf("case %d: expected %t, got %t for %v", i, tc.transient, got, tc.err)
+		}
+	}
+}
+
+func TestRetryRead(t *testing.T) {
+	srv, client := testServerClient(t)
+	defer srv.Close()
+
+	cases := []struct {
+		errs  []error
+		calls int
+		fails bool
+	}{
+		{[]error{&statuscake.NotFoundError{Kind: "Test", ID: "1"}}, 2, false},
+		{[]error{&statuscake.HTTPError{StatusCode: 503}}, 2, false},
+		{[]error{&statuscake.RateLimitError{HTTPError: statuscake.HTTPError{StatusCode: 429}, RetryAfter: 10 * time.Millisecond}}, 2, false},
+		{[]error{&statuscake.AuthenticationError{Message: "bad key"}}, 1, true},
+		{[]error{&statuscake.HTTPError{StatusCode: 403}}, 1, true},
+		{[]error{&statuscake.UpdateError{Message: "Validation failed"}}, 1, true},
+	}
+
+	for i, tc := range cases {
+		calls := 0
+		err := retryRead(client, time.Minute, "StatusCake Test 1", func() (string, error) {
+			calls++
+			if calls <= len(tc.errs) {
+				return "", tc.errs[calls-1]
+			}
+			return "", nil
+		})
+		if (err != nil) != tc.fails {
+			t.Errorf("case %d: expected failure %t, got %v", i, tc.fails, err)
+		}
+		if calls != tc.calls {
+			t.Errorf("case %d: expected %d reads, got %d", i, tc.calls, calls)
+		}
+	}
+}
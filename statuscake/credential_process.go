@@ -0,0 +1,110 @@
+package statuscake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+const (
+	// credentialProcessTimeout is how long the command may run.
+	credentialProcessTimeout = 1 * time.Minute
+
+	// credentialExpiryWindow is how long before they expire credentials are
+	// fetched again, so they do not expire during a request.
+	credentialExpiryWindow = 1 * time.Minute
+)
+
+// credentialProcessOutput is what the credential_process command prints.
+type credentialProcessOutput struct {
+	Username   string    `json:"username"`
+	Apikey     string    `json:"apikey"`
+	Token      string    `json:"token"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// credentialProcess runs the credential_process command, and again whenever
+// the credentials it printed expire. Its output is never logged.
+type credentialProcess struct {
+	command string
+	ctx     context.Context
+
+	mu      sync.Mutex
+	auth    statuscake.Auth
+	expires time.Time
+}
+
+func newCredentialProcess(ctx context.Context, command string) *credentialProcess {
+	return &credentialProcess{command: command, ctx: ctx}
+}
+
+// Credentials returns the current credentials, running the command first if
+// they have not been fetched yet or are about to expire.
+func (p *credentialProcess) Credentials() (statuscake.Auth, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fetched := p.auth != statuscake.Auth{}
+	if fetched && (p.expires.IsZero() || time.Until(p.expires) > credentialExpiryWindow) {
+		return p.auth, nil
+	}
+
+	out, err := p.run()
+	if err != nil {
+		return statuscake.Auth{}, err
+	}
+
+	p.auth = statuscake.Auth{Username: out.Username, Apikey: out.Apikey, Token: out.Token}
+	p.expires = out.Expiration
+	if !p.expires.IsZero() {
+		log.Printf("[DEBUG] StatusCake credentials from credential_process expire at %s", p.expires.Format(time.RFC3339))
+	}
+
+	return p.auth, nil
+}
+
+func (p *credentialProcess) run() (*credentialProcessOutput, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", p.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	log.Printf("[DEBUG] Running credential_process")
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, fmt.Errorf("Error running credential_process: %s", err)
+	}
+
+	// The output is not quoted in errors, as it holds the credentials.
+	var out credentialProcessOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("Error parsing credential_process output: expected a JSON object with username and apikey, or token")
+	}
+	if out.Token == "" && (out.Username == "" || out.Apikey == "") {
+		return nil, fmt.Errorf("credential_process output has neither a token nor both username and apikey")
+	}
+	if !out.Expiration.IsZero() && time.Until(out.Expiration) <= 0 {
+		return nil, fmt.Errorf("credential_process returned credentials that expired at %s", out.Expiration.Format(time.RFC3339))
+	}
+
+	return &out, nil
+}
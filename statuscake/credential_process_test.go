@@ -0,0 +1,113 @@
+package statuscake
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+// newTestCredentialProcess returns a credential process standing in for a
+// secrets manager: a script printing output and counting its runs.
+func newTestCredentialProcess(t *testing.T, output string) (*credentialProcess, func() int, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stand-in credential process is a shell script")
+	}
+
+	dir, err := ioutil.TempDir("", "statuscake")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	outputFile := filepath.Join(dir, "output.json")
+	if err := ioutil.WriteFile(outputFile, []byte(output), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	runs := filepath.Join(dir, "runs")
+	command := fmt.Sprintf("echo run >> %s; cat %s", runs, outputFile)
+
+	count := func() int {
+		b, _ := ioutil.ReadFile(runs)
+		return strings.Count(string(b), "run")
+	}
+
+	return newCredentialProcess(context.Background(), command), count, func() { os.RemoveAll(dir) }
+}
+
+func TestCredentialProcess(t *testing.T) {
+	cases := []struct {
+		name     string
+		output   string
+		expected statuscake.Auth
+		runs     int
+	}{
+		{
+			name:     "no expiration",
+			output:   `{"username": "user", "apikey": "key"}`,
+			expected: statuscake.Auth{Username: "user", Apikey: "key"},
+			runs:     1,
+		},
+		{
+			name:     "valid token",
+			output:   fmt.Sprintf(`{"token": "token", "expiration": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339)),
+			expected: statuscake.Auth{Token: "token"},
+			runs:     1,
+		},
+		{
+			name:     "expiring",
+			output:   fmt.Sprintf(`{"username": "user", "apikey": "key", "expiration": %q}`, time.Now().Add(credentialExpiryWindow/2).Format(time.RFC3339)),
+			expected: statuscake.Auth{Username: "user", Apikey: "key"},
+			runs:     3,
+		},
+	}
+
+	for _, tc := range cases {
+		process, runs, cleanup := newTestCredentialProcess(t, tc.output)
+		for i := 0; i < 3; i++ {
+			auth, err := process.Credentials()
+			if err != nil {
+				t.Fatalf("%s: err: %s", tc.name, err)
+			}
+			if auth != tc.expected {
+				t.Errorf("%s: expected %+v, got %+v", tc.name, tc.expected, auth)
+			}
+		}
+		if n := runs(); n != tc.runs {
+			t.Errorf("%s: expected the command to run %d times, got %d", tc.name, tc.runs, n)
+		}
+		cleanup()
+	}
+}
+
+func TestCredentialProcess_invalid(t *testing.T) {
+	outputs := []string{
+		`not json, secret-key`,
+		`{"username": "user", "apikey": ""}`,
+		fmt.Sprintf(`{"token": "secret-key", "expiration": %q}`, time.Now().Add(-time.Hour).Format(time.RFC3339)),
+	}
+
+	for _, output := range outputs {
+		process, _, cleanup := newTestCredentialProcess(t, output)
+		_, err := process.Credentials()
+		cleanup()
+
+		if err == nil {
+			t.Errorf("expected an error for %s", output)
+			continue
+		}
+		if strings.Contains(err.Error(), "secret-key") {
+			t.Errorf("error quotes the command output: %s", err)
+		}
+	}
+
+	process := newCredentialProcess(context.Background(), "exit 3")
+	if _, err := process.Credentials(); err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("expected the exit status in the error, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package statuscake
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const (
+	defaultProfile         = "default"
+	defaultCredentialsFile = "~/.statuscake/credentials"
+)
+
+// credentialFields are the provider arguments that can come from the
+// environment or a credentials file, with their environment variables.
+var credentialFields = []struct {
+	name, env string
+}{
+	{"username", "STATUSCAKE_USERNAME"},
+	{"apikey", "STATUSCAKE_APIKEY"},
+}
+
+// profile holds the values of one profile of a credentials file.
+type profile map[string]string
+
+// resolveCredentials returns the username and apikey to use. Each is taken
+// from the first source setting it: the provider block, then its
+// environment variable, then the selected profile of the credentials file.
+func resolveCredentials(d *schema.ResourceData) (map[string]string, error) {
+	name, explicit := d.Get("profile").(string), true
+	if name == "" {
+		name, explicit = defaultProfile, false
+	}
+	path := d.Get("shared_credentials_file").(string)
+	if path == "" {
+		path = defaultCredentialsFile
+	}
+
+	prof, fileErr := loadProfile(path, name)
+	if fileErr != nil && explicit {
+		return nil, fileErr
+	}
+
+	creds := make(map[string]string, len(credentialFields))
+	for _, f := range credentialFields {
+		if v := d.Get(f.name).(string); v != "" {
+			creds[f.name] = v
+			continue
+		}
+		if v := os.Getenv(f.env); v != "" {
+			creds[f.name] = v
+			continue
+		}
+		if v := prof[f.name]; v != "" {
+			creds[f.name] = v
+			continue
+		}
+
+		fileSource := fmt.Sprintf("profile %q of %s", name, path)
+		if fileErr != nil {
+			fileSource = fmt.Sprintf("%s (%s)", fileSource, fileErr)
+		}
+		return nil, fmt.Errorf("No %s found: set %s in the provider block, the %s environment variable, or %s in %s",
+			f.name, f.name, f.env, f.name, fileSource)
+	}
+
+	return creds, nil
+}
+
+// loadProfile reads one profile of a credentials file, in INI or JSON
+// format.
+func loadProfile(path, name string) (profile, error) {
+	expanded, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading credentials file: %s", err)
+	}
+
+	var profiles map[string]profile
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &profiles); err != nil {
+			return nil, fmt.Errorf("Error parsing credentials file %s: %s", path, err)
+		}
+	} else if profiles, err = parseINIProfiles(b); err != nil {
+		return nil, fmt.Errorf("Error parsing credentials file %s: %s", path, err)
+	}
+
+	prof, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in credentials file %s", name, path)
+	}
+
+	return prof, nil
+}
+
+// parseINIProfiles parses "[name]" sections of "key = value" lines. Lines
+// starting with # or ; are comments.
+func parseINIProfiles(b []byte) (map[string]profile, error) {
+	profiles := make(map[string]profile)
+	var current profile
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[' && line[len(line)-1] == ']':
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = make(profile)
+			}
+			current = profiles[name]
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: %s is not in a [profile] section", n, strings.TrimSpace(kv[0]))
+		}
+		current[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return profiles, scanner.Err()
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("Error finding the home directory for %s: %s", path, err)
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
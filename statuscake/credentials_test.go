@@ -0,0 +1,169 @@
+package statuscake
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const testINICredentials = `
+# StatusCake accounts
+[default]
+username = default-user
+apikey = default-key
+
+[staging]
+username = staging-user
+; the key is rotated monthly
+apikey = staging-key
+`
+
+const testJSONCredentials = `{
+  "default": {"username": "default-user", "apikey": "default-key"},
+  "staging": {"username": "staging-user", "apikey": "staging-key"}
+}`
+
+// setCredentialEnv sets the credential environment variables for the
+// duration of a test, unsetting those given as "".
+func setCredentialEnv(t *testing.T, env map[string]string) func() {
+	saved := make(map[string]*string)
+	for k, v := range env {
+		if old, ok := os.LookupEnv(k); ok {
+			saved[k] = &old
+		} else {
+			saved[k] = nil
+		}
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+	}
+
+	return func() {
+		for k, v := range saved {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+func writeCredentialsFile(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "statuscake")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	path := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestResolveCredentials(t *testing.T) {
+	iniPath, cleanupINI := writeCredentialsFile(t, testINICredentials)
+	defer cleanupINI()
+	jsonPath, cleanupJSON := writeCredentialsFile(t, testJSONCredentials)
+	defer cleanupJSON()
+
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		env      map[string]string
+		username string
+		apikey   string
+		err      string
+	}{
+		{
+			name:     "default profile",
+			config:   map[string]interface{}{"shared_credentials_file": iniPath},
+			username: "default-user",
+			apikey:   "default-key",
+		},
+		{
+			name:     "named profile from JSON",
+			config:   map[string]interface{}{"shared_credentials_file": jsonPath, "profile": "staging"},
+			username: "staging-user",
+			apikey:   "staging-key",
+		},
+		{
+			name:     "profile from the environment",
+			config:   map[string]interface{}{"shared_credentials_file": iniPath},
+			env:      map[string]string{"STATUSCAKE_PROFILE": "staging"},
+			username: "staging-user",
+			apikey:   "staging-key",
+		},
+		{
+			name:     "environment over file",
+			config:   map[string]interface{}{"shared_credentials_file": iniPath},
+			env:      map[string]string{"STATUSCAKE_APIKEY": "env-key"},
+			username: "default-user",
+			apikey:   "env-key",
+		},
+		{
+			name:     "provider block over environment",
+			config:   map[string]interface{}{"shared_credentials_file": iniPath, "apikey": "hcl-key"},
+			env:      map[string]string{"STATUSCAKE_APIKEY": "env-key"},
+			username: "default-user",
+			apikey:   "hcl-key",
+		},
+		{
+			name:     "no file needed",
+			config:   map[string]interface{}{"shared_credentials_file": "/nonexistent", "username": "user", "apikey": "key"},
+			username: "user",
+			apikey:   "key",
+		},
+		{
+			name:   "missing apikey",
+			config: map[string]interface{}{"shared_credentials_file": "/nonexistent", "username": "user"},
+			err:    `No apikey found: set apikey in the provider block, the STATUSCAKE_APIKEY environment variable, or apikey in profile "default" of /nonexistent`,
+		},
+		{
+			name:   "missing profile",
+			config: map[string]interface{}{"shared_credentials_file": iniPath, "profile": "prod", "username": "user", "apikey": "key"},
+			err:    `profile "prod" not found`,
+		},
+	}
+
+	for _, tc := range cases {
+		env := map[string]string{"STATUSCAKE_USERNAME": "", "STATUSCAKE_APIKEY": "", "STATUSCAKE_PROFILE": ""}
+		for k, v := range tc.env {
+			env[k] = v
+		}
+		restore := setCredentialEnv(t, env)
+
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, tc.config)
+		creds, err := resolveCredentials(d)
+		restore()
+
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected an error containing %q, got %v", tc.name, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: err: %s", tc.name, err)
+			continue
+		}
+		if creds["username"] != tc.username || creds["apikey"] != tc.apikey {
+			t.Errorf("%s: expected %s/%s, got %s/%s", tc.name, tc.username, tc.apikey, creds["username"], creds["apikey"])
+		}
+	}
+}
+
+func TestParseINIProfiles_invalid(t *testing.T) {
+	for _, content := range []string{"username = outside", "[default]\napikey"} {
+		if _, err := parseINIProfiles([]byte(content)); err == nil {
+			t.Errorf("expected an error parsing %q", content)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package statuscake
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceStatusCakeAccount() *schema.Resource {
+	return &schema.Resource{
+		Read: readAccount,
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"first_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"last_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"plan": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"test_quota": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"tests_used": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func readAccount(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*StatusCakeClient)
+
+	account, err := client.Account()
+	if err != nil {
+		return fmt.Errorf("Error reading StatusCake account: %s", describeAPIError(err, nil))
+	}
+	tests, err := client.Tests().AllListed()
+	if err != nil {
+		return fmt.Errorf("Error listing StatusCake tests: %s", err)
+	}
+
+	// The v1 API does not tell which account a token belongs to.
+	if account.Username != "" {
+		d.SetId(account.Username)
+	} else {
+		d.SetId("current")
+	}
+	d.Set("username", account.Username)
+	d.Set("first_name", account.FirstName)
+	d.Set("last_name", account.LastName)
+	d.Set("email", account.Email)
+	d.Set("plan", account.Plan)
+	d.Set("test_quota", account.MaxTests)
+	d.Set("tests_used", len(tests))
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package statuscake
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
+)
+
+func TestReadAccount(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	c, err := statuscake.New(statuscake.Auth{Username: statuscaketest.Username, Apikey: statuscaketest.Apikey},
+		statuscake.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &StatusCakeClient{Client: c, providerState: &providerState{deletes: &deleteLedger{}}}
+
+	test := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, map[string]interface{}{
+		"website_name": "account",
+		"website_url":  "https://example.com",
+		"test_type":    "HTTP",
+		"check_rate":   300,
+	})
+	if err := CreateTest(test, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceStatusCakeAccount().Schema, map[string]interface{}{})
+	if err := readAccount(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"username":   statuscaketest.Username,
+		"plan":       "SUPERIOR",
+		"test_quota": 100,
+		"tests_used": 1,
+	}
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("expected %s %v, got %v", k, v, got)
+		}
+	}
+
+	bad, _ := statuscake.New(statuscake.Auth{Username: "someone", Apikey: "wrong"}, statuscake.WithBaseURL(srv.URL))
+	if _, err := bad.Account(); err == nil {
+		t.Error("expected wrong credentials to be rejected")
+	} else if _, ok := describeCredentialError(err).(*apiError); !ok {
+		t.Errorf("expected an explained credential error, got %T: %s", err, err)
+	}
+}
+
+func TestAccStatusCakeAccount_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.statuscake_account.current", "username"),
+					resource.TestCheckResourceAttrSet("data.statuscake_account.current", "plan"),
+					resource.TestCheckResourceAttrSet("data.statuscake_account.current", "tests_used"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAccountConfig_basic = `
+data "statuscake_account" "current" {}
+`
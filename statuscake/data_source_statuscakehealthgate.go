@@ -0,0 +1,230 @@
+package statuscake
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func dataSourceStatusCakeHealthGate() *schema.Resource {
+	return &schema.Resource{
+		Read: readHealthGate,
+
+		Schema: map[string]*schema.Schema{
+			"test_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				Set:      schema.HashString,
+			},
+
+			"tags": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				Set:      schema.HashString,
+			},
+
+			"match_any_tag": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"max_down": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"min_uptime": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatBetween(0, 100),
+			},
+
+			"include_paused": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"checked_test_ids": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"down_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// healthGatePolicy is the policy a health gate checks the selected tests
+// against.
+type healthGatePolicy struct {
+	MaxDown       int
+	MinUptime     float64
+	IncludePaused bool
+}
+
+// evaluate returns the tests that break the policy, or nil when the gate
+// passes. Every test that is down or below the minimum uptime is returned
+// once the policy is broken, so the error shows the whole picture.
+func (p healthGatePolicy) evaluate(tests []*statuscake.Test) (failing []*statuscake.Test, down int) {
+	var lowUptime bool
+
+	for _, t := range tests {
+		if t.Paused && !p.IncludePaused {
+			continue
+		}
+
+		isDown := t.Status != "Up"
+		isLow := t.Uptime < p.MinUptime
+		if isDown {
+			down++
+		}
+		if isLow {
+			lowUptime = true
+		}
+		if isDown || isLow {
+			failing = append(failing, t)
+		}
+	}
+
+	if down <= p.MaxDown && !lowUptime {
+		return nil, down
+	}
+
+	return failing, down
+}
+
+func readHealthGate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*StatusCakeClient)
+
+	testIDs := castSetToSliceStrings(d.Get("test_ids").(*schema.Set).List())
+	tags := castSetToSliceStrings(d.Get("tags").(*schema.Set).List())
+	if len(testIDs) == 0 && len(tags) == 0 {
+		return fmt.Errorf("Error reading StatusCake health gate: one of test_ids or tags must be set")
+	}
+
+	tests, err := selectHealthGateTests(client, testIDs, tags, d.Get("match_any_tag").(bool))
+	if err != nil {
+		return err
+	}
+
+	policy := healthGatePolicy{
+		MaxDown:       d.Get("max_down").(int),
+		MinUptime:     d.Get("min_uptime").(float64),
+		IncludePaused: d.Get("include_paused").(bool),
+	}
+	failing, down := policy.evaluate(tests)
+	if failing != nil {
+		return fmt.Errorf("StatusCake health gate failed: %d of %d tests down (max_down %d, min_uptime %.2f%%):\n%s",
+			down, len(tests), policy.MaxDown, policy.MinUptime, describeFailingTests(client, failing))
+	}
+	log.Printf("[DEBUG] StatusCake health gate passed: %d of %d tests down", down, len(tests))
+
+	ids := make([]string, len(tests))
+	for i, t := range tests {
+		ids[i] = strconv.Itoa(t.TestID)
+	}
+	sort.Strings(ids)
+
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(ids, ","))))
+	d.Set("checked_test_ids", ids)
+	d.Set("down_count", down)
+
+	return nil
+}
+
+// selectHealthGateTests lists the tests to check in one request, filtered by
+// tag on the API side and by ID here. Every requested ID must exist.
+func selectHealthGateTests(client *StatusCakeClient, testIDs, tags []string, matchAny bool) ([]*statuscake.Test, error) {
+	filter := url.Values{}
+	if len(tags) > 0 {
+		sort.Strings(tags)
+		filter.Set("tags", strings.Join(tags, ","))
+		if matchAny {
+			filter.Set("matchany", "true")
+		}
+	}
+
+	tests, err := client.Tests().AllWithFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing StatusCake tests for health gate: %s", err)
+	}
+
+	if len(testIDs) == 0 {
+		if len(tests) == 0 {
+			return nil, fmt.Errorf("Error reading StatusCake health gate: no tests are tagged %s", strings.Join(tags, ", "))
+		}
+		return tests, nil
+	}
+
+	byID := make(map[string]*statuscake.Test, len(tests))
+	for _, t := range tests {
+		byID[strconv.Itoa(t.TestID)] = t
+	}
+
+	var selected []*statuscake.Test
+	var missing []string
+	for _, id := range testIDs {
+		if t, ok := byID[id]; ok {
+			selected = append(selected, t)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("Error reading StatusCake health gate: tests %s not found%s",
+			strings.Join(missing, ", "), tagScope(tags))
+	}
+
+	return selected, nil
+}
+
+func tagScope(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" among tests tagged %s", strings.Join(tags, ", "))
+}
+
+// describeFailingTests lists the failing tests. The test list does not say
+// when a test was last checked, so the details of each failing test are read
+// for it.
+func describeFailingTests(client *StatusCakeClient, tests []*statuscake.Test) string {
+	lines := make([]string, len(tests))
+	for i, t := range tests {
+		lastTested := t.LastTested
+		if lastTested == "" {
+			if detail, err := client.Tests().Detail(t.TestID); err != nil {
+				log.Printf("[WARN] Error getting StatusCake Test details for %d: %s", t.TestID, err)
+			} else {
+				lastTested = detail.LastTested
+			}
+		}
+		if lastTested == "" {
+			lastTested = "unknown"
+		}
+		lines[i] = fmt.Sprintf("  - Test %d (%q): %s, uptime %.2f%%, last tested %s",
+			t.TestID, t.WebsiteName, t.Status, t.Uptime, lastTested)
+	}
+
+	return strings.Join(lines, "\n")
+}
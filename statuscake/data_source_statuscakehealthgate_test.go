@@ -0,0 +1,120 @@
+package statuscake
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func TestHealthGatePolicy_evaluate(t *testing.T) {
+	tests := []*statuscake.Test{
+		{TestID: 1, Status: "Up", Uptime: 100},
+		{TestID: 2, Status: "Down", Uptime: 97.5},
+		{TestID: 3, Status: "Up", Uptime: 99.1},
+		{TestID: 4, Status: "Down", Uptime: 0, Paused: true},
+	}
+
+	cases := []struct {
+		policy   healthGatePolicy
+		failing  []int
+		expected int
+	}{
+		{healthGatePolicy{}, []int{2}, 1},
+		{healthGatePolicy{MaxDown: 1}, nil, 1},
+		{healthGatePolicy{MaxDown: 1, MinUptime: 99}, []int{2}, 1},
+		{healthGatePolicy{MaxDown: 1, MinUptime: 99.5}, []int{2, 3}, 1},
+		{healthGatePolicy{MaxDown: 1, IncludePaused: true}, []int{2, 4}, 2},
+	}
+
+	for _, tc := range cases {
+		failing, down := tc.policy.evaluate(tests)
+		if down != tc.expected {
+			t.Errorf("%+v: expected %d down, got %d", tc.policy, tc.expected, down)
+		}
+
+		var ids []int
+		for _, f := range failing {
+			ids = append(ids, f.TestID)
+		}
+		if len(ids) != len(tc.failing) {
+			t.Errorf("%+v: expected failing tests %v, got %v", tc.policy, tc.failing, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tc.failing[i] {
+				t.Errorf("%+v: expected failing tests %v, got %v", tc.policy, tc.failing, ids)
+			}
+		}
+	}
+}
+
+func TestDescribeFailingTests(t *testing.T) {
+	srv, client := testServerClient(t)
+	defer srv.Close()
+
+	var ids []int
+	for _, name := range []string{"api", "web", "ok"} {
+		test, err := client.Tests().Update(&statuscake.Test{WebsiteName: name, WebsiteURL: "https://" + name + ".example.com", TestType: "HTTP"})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		ids = append(ids, test.TestID)
+	}
+
+	// As listed: without the last tested time
+	out := describeFailingTests(client, []*statuscake.Test{
+		{TestID: ids[0], WebsiteName: "api", Status: "Down", Uptime: 97.5},
+		{TestID: ids[1], WebsiteName: "web", Status: "Down"},
+		{TestID: 1, WebsiteName: "gone", Status: "Down"},
+	})
+
+	for _, want := range []string{
+		fmt.Sprintf(`Test %d ("api"): Down, uptime 97.50%%, last tested 2019-10-19 10:01:00`, ids[0]),
+		fmt.Sprintf(`Test %d ("web"): Down, uptime 0.00%%, last tested 2019-10-19 10:02:00`, ids[1]),
+		`Test 1 ("gone"): Down, uptime 0.00%, last tested unknown`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if n := srv.Requests("GET /Tests/Details"); n != 3 {
+		t.Errorf("expected the details of the 3 failing tests to be read, got %d requests", n)
+	}
+}
+
+func TestAccStatusCakeHealthGate_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: interpolateTerraformTemplate(testAccHealthGateConfig_basic),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.statuscake_health_gate.google", "checked_test_ids.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.statuscake_health_gate.google", "checked_test_ids.0",
+						"statuscake_test.google", "test_id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccHealthGateConfig_basic = `
+resource "statuscake_test" "google" {
+	website_name = "google.com"
+	website_url = "https://www.google.com"
+	test_type = "HTTP"
+	contact_group = ["%s"]
+	wait_for_status = "Up"
+}
+
+data "statuscake_health_gate" "google" {
+	test_ids = ["${statuscake_test.google.test_id}"]
+	max_down = 1
+}
+`
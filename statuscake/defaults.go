@@ -0,0 +1,308 @@
+package statuscake
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// defaultableTestAttributes are the statuscake_test attributes the provider
+// defaults block may supply, with the value used when neither the resource
+// nor the provider sets them. Sets without a default are sent empty, leaving
+// the choice to the API.
+var defaultableTestAttributes = []struct {
+	key      string
+	fallback interface{}
+}{
+	{"contact_group", nil},
+	{"node_locations", nil},
+	{"check_rate", 300},
+	{"timeout", 40},
+	{"confirmations", 0},
+	{"trigger_rate", 5},
+}
+
+// testDefaults holds the provider defaults blocks, keyed by upper case
+// test_type with "" for the block that applies to every test type.
+type testDefaults map[string]map[string]interface{}
+
+func providerDefaultsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Values used for statuscake_test attributes that a resource leaves unset.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"test_type": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Only apply these defaults to tests of this type.",
+				},
+				"contact_group": {
+					Type:     schema.TypeSet,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Optional: true,
+					Set:      schema.HashString,
+				},
+				"node_locations": {
+					Type:     schema.TypeSet,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Optional: true,
+					Set:      schema.HashString,
+				},
+				"check_rate": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"timeout": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"confirmations": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"trigger_rate": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// expandTestDefaults converts the provider defaults blocks. Zero values and
+// empty sets are treated as not set.
+func expandTestDefaults(blocks []interface{}) (testDefaults, error) {
+	defaults := make(testDefaults, len(blocks))
+
+	for _, raw := range blocks {
+		block, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		testType := strings.ToUpper(block["test_type"].(string))
+		if _, ok := defaults[testType]; ok {
+			if testType == "" {
+				return nil, fmt.Errorf("defaults: only one block may omit test_type")
+			}
+			return nil, fmt.Errorf("defaults: test_type %q is set in more than one block", testType)
+		}
+
+		values := make(map[string]interface{})
+		for _, attr := range defaultableTestAttributes {
+			switch v := block[attr.key].(type) {
+			case *schema.Set:
+				if v.Len() > 0 {
+					values[attr.key] = castSetToSliceStrings(v.List())
+				}
+			case int:
+				if v != 0 {
+					values[attr.key] = v
+				}
+			}
+		}
+		defaults[testType] = values
+	}
+
+	return defaults, nil
+}
+
+// lookup returns the default for key, preferring a block for testType over
+// the block that applies to every test type.
+func (t testDefaults) lookup(testType, key string) (interface{}, bool) {
+	for _, tt := range []string{strings.ToUpper(testType), ""} {
+		if v, ok := t[tt][key]; ok {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// applyTestDefaults records in applied_defaults the value every defaultable
+// attribute left out of the configuration gets from the provider defaults,
+// falling back to the built in defaults.
+//
+// The attributes are not computed, so a value removed from the configuration
+// plans a change back to its default, and their state only ever holds what
+// was configured. Changing the provider defaults plans an update of every
+// test that uses them.
+func applyTestDefaults(d *schema.ResourceDiff, meta interface{}) error {
+	client := meta.(*StatusCakeClient)
+	testType := d.Get("test_type").(string)
+
+	applied := make(map[string]interface{})
+	for _, attr := range defaultableTestAttributes {
+		// Explicit zero values and empty sets win over the defaults too
+		if inConfig(d, attr.key) {
+			continue
+		}
+
+		value := attr.fallback
+		if v, ok := client.testDefaults.lookup(testType, attr.key); ok && !hasAlternative(d, attr.key) {
+			value = v
+		}
+		if value == nil {
+			continue
+		}
+		applied[attr.key] = formatDefault(value)
+	}
+
+	if reflect.DeepEqual(d.Get("applied_defaults").(map[string]interface{}), applied) {
+		return nil
+	}
+
+	return d.SetNew("applied_defaults", applied)
+}
+
+// inConfig reports whether key is set in the configuration, even to a zero
+// value. A removed attribute reads from the diff like an explicit zero, and
+// ResourceDiff has no accessor for its configuration, so it is looked up in
+// the configuration through reflection. TestInConfig fails if the vendored
+// SDK changes the field.
+func inConfig(d *schema.ResourceDiff, key string) bool {
+	config := reflect.ValueOf(d).Elem().FieldByName("config")
+	if !config.IsValid() || config.IsNil() {
+		_, ok := d.GetOk(key)
+		return ok
+	}
+
+	values := config.Elem().FieldByName("Config")
+	if values.IsNil() {
+		return false
+	}
+
+	return values.MapIndex(reflect.ValueOf(key)).IsValid()
+}
+
+// upgradeTestStateV0 moves the defaults that version 0 stored in the
+// attributes themselves into applied_defaults, so upgrading the provider does
+// not plan a change to every test. A value equal to the built in default is
+// taken to be that default, and an empty set to be unset.
+func upgradeTestStateV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	applied := make(map[string]interface{})
+	for _, attr := range defaultableTestAttributes {
+		v, ok := rawState[attr.key]
+		if !ok || v == nil {
+			continue
+		}
+
+		if list, ok := v.([]interface{}); ok {
+			if len(list) == 0 {
+				delete(rawState, attr.key)
+			}
+			continue
+		}
+		if attr.fallback != nil && fmt.Sprint(v) == formatDefault(attr.fallback) {
+			applied[attr.key] = formatDefault(attr.fallback)
+			delete(rawState, attr.key)
+		}
+	}
+	rawState["applied_defaults"] = applied
+	// States from before location_selector have no resolved locations, which
+	// would plan them as unknown.
+	if rawState["resolved_node_locations"] == nil {
+		rawState["resolved_node_locations"] = []interface{}{}
+	}
+
+	return rawState, nil
+}
+
+// formatDefault converts a default to its applied_defaults form, joining sets
+// with commas.
+func formatDefault(value interface{}) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case []string:
+		sorted := append([]string(nil), v...)
+		sort.Strings(sorted)
+		return strings.Join(sorted, ",")
+	}
+
+	return fmt.Sprint(value)
+}
+
+// appliedDefault returns the default applied to key, if it was left out of
+// the configuration.
+func appliedDefault(d *schema.ResourceData, key string) (string, bool) {
+	v, ok := d.Get("applied_defaults").(map[string]interface{})[key]
+	if !ok {
+		return "", false
+	}
+
+	return v.(string), true
+}
+
+// testInt returns the value of a defaultable int attribute to send to the
+// API: the default applied to it, or else its configured value.
+func testInt(d *schema.ResourceData, key string) int {
+	if v, ok := appliedDefault(d, key); ok {
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+
+	return d.Get(key).(int)
+}
+
+// testStrings does the same for defaultable sets.
+func testStrings(d *schema.ResourceData, key string) []string {
+	if v, ok := appliedDefault(d, key); ok {
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	}
+
+	return castSetToSliceStrings(d.Get(key).(*schema.Set).List())
+}
+
+// plannedTestStrings is testStrings for a plan.
+func plannedTestStrings(d *schema.ResourceDiff, key string) []string {
+	if v, ok := d.Get("applied_defaults").(map[string]interface{})[key]; ok {
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v.(string), ",")
+	}
+
+	return castSetToSliceStrings(d.Get(key).(*schema.Set).List())
+}
+
+// setTestDefaultables stores the values read from the API: in
+// applied_defaults for attributes that were defaulted, so a drift from the
+// default plans an update, and in the attribute itself otherwise.
+func setTestDefaultables(d *schema.ResourceData, values map[string]interface{}) error {
+	applied := d.Get("applied_defaults").(map[string]interface{})
+	for key, value := range values {
+		if _, ok := applied[key]; ok {
+			applied[key] = formatDefault(value)
+			continue
+		}
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("Error setting %s: %s", key, err)
+		}
+	}
+
+	return d.Set("applied_defaults", applied)
+}
+
+// hasAlternative reports whether an attribute that takes the place of key is
+// set, in which case key must not be defaulted.
+func hasAlternative(d *schema.ResourceDiff, key string) bool {
+	switch key {
+	case "contact_group":
+		_, ok := d.GetOk("contact_id")
+		return ok
+	case "node_locations":
+		return getLocationSelector(d) != nil
+	}
+
+	return false
+}
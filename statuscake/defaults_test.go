@@ -0,0 +1,319 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestExpandTestDefaults(t *testing.T) {
+	defaults, err := expandTestDefaults([]interface{}{
+		map[string]interface{}{
+			"test_type":      "",
+			"contact_group":  schema.NewSet(schema.HashString, []interface{}{"1"}),
+			"node_locations": schema.NewSet(schema.HashString, nil),
+			"check_rate":     60,
+			"timeout":        0,
+			"confirmations":  0,
+			"trigger_rate":   0,
+		},
+		map[string]interface{}{
+			"test_type":      "tcp",
+			"contact_group":  schema.NewSet(schema.HashString, nil),
+			"node_locations": schema.NewSet(schema.HashString, nil),
+			"check_rate":     0,
+			"timeout":        20,
+			"confirmations":  0,
+			"trigger_rate":   0,
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if v, ok := defaults.lookup("TCP", "timeout"); !ok || v != 20 {
+		t.Fatalf("expected the TCP timeout, got %v", v)
+	}
+	if v, ok := defaults.lookup("TCP", "check_rate"); !ok || v != 60 {
+		t.Fatalf("expected TCP tests to fall back to the shared check_rate, got %v", v)
+	}
+	if v, ok := defaults.lookup("HTTP", "timeout"); ok {
+		t.Fatalf("expected no HTTP timeout, got %v", v)
+	}
+	if _, ok := defaults.lookup("HTTP", "node_locations"); ok {
+		t.Fatalf("expected empty sets to be treated as unset")
+	}
+
+	_, err = expandTestDefaults([]interface{}{
+		map[string]interface{}{"test_type": "HTTP"},
+		map[string]interface{}{"test_type": "http"},
+	})
+	if err == nil {
+		t.Fatalf("expected an error for duplicate test_type blocks")
+	}
+}
+
+func TestApplyTestDefaults(t *testing.T) {
+	meta := defaultsTestMeta(testDefaults{
+		"":    {"check_rate": 60, "contact_group": []string{"1"}},
+		"TCP": {"timeout": 20},
+	})
+
+	diff := diffTestDefaults(t, meta, nil, map[string]interface{}{
+		"website_name":  "example",
+		"website_url":   "example.com",
+		"test_type":     "TCP",
+		"contact_group": []interface{}{"2"},
+		"confirmations": 0,
+	})
+
+	expected := map[string]string{
+		"applied_defaults.%":            "3",
+		"applied_defaults.check_rate":   "60",
+		"applied_defaults.timeout":      "20",
+		"applied_defaults.trigger_rate": "5",
+		"contact_group.#":               "1",
+	}
+	for k, v := range expected {
+		attr, ok := diff.Attributes[k]
+		if !ok {
+			t.Fatalf("expected a diff for %s", k)
+		}
+		if attr.NewComputed || attr.New != v {
+			t.Fatalf("expected %s to be %q, got %#v", k, v, attr)
+		}
+	}
+	for k, attr := range diff.Attributes {
+		if strings.HasPrefix(k, "contact_group.") && k != "contact_group.#" && attr.New != "2" {
+			t.Fatalf("expected the configured contact_group to win over the default, got %s = %q", k, attr.New)
+		}
+		if k == "check_rate" || k == "timeout" || k == "trigger_rate" {
+			t.Fatalf("expected defaulted %s to stay out of the attribute, got %#v", k, attr)
+		}
+	}
+}
+
+func TestApplyTestDefaults_explicitZero(t *testing.T) {
+	meta := defaultsTestMeta(testDefaults{"": {"confirmations": 3, "contact_group": []string{"1"}}})
+
+	diff := diffTestDefaults(t, meta, nil, map[string]interface{}{
+		"website_name":  "example",
+		"website_url":   "example.com",
+		"test_type":     "HTTP",
+		"confirmations": 0,
+		"contact_group": []interface{}{},
+	})
+
+	for _, key := range []string{"applied_defaults.confirmations", "applied_defaults.contact_group"} {
+		if attr, ok := diff.Attributes[key]; ok {
+			t.Errorf("expected the explicit zero value to win over the default, got %s = %#v", key, attr)
+		}
+	}
+
+	// Left out, the same attributes take the defaults
+	diff = diffTestDefaults(t, meta, nil, map[string]interface{}{
+		"website_name": "example",
+		"website_url":  "example.com",
+		"test_type":    "HTTP",
+	})
+	for key, v := range map[string]string{"applied_defaults.confirmations": "3", "applied_defaults.contact_group": "1"} {
+		if attr := diff.Attributes[key]; attr == nil || attr.New != v {
+			t.Errorf("expected %s to be %q, got %#v", key, v, attr)
+		}
+	}
+}
+
+func TestInConfig(t *testing.T) {
+	r := resourceStatusCakeTest()
+	r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+		if !inConfig(d, "confirmations") || inConfig(d, "timeout") {
+			t.Errorf("expected only confirmations to be found in the configuration")
+		}
+		return nil
+	}
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"website_name":  "example",
+		"website_url":   "example.com",
+		"test_type":     "HTTP",
+		"confirmations": 0,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state := &terraform.InstanceState{ID: "1", Attributes: map[string]string{"id": "1", "timeout": "40"}}
+	if _, err := r.Diff(state, terraform.NewResourceConfig(raw), nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestApplyTestDefaults_update(t *testing.T) {
+	config := map[string]interface{}{
+		"website_name": "example",
+		"website_url":  "example.com",
+		"test_type":    "HTTP",
+	}
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                             "1",
+			"website_name":                   "example",
+			"website_url":                    "example.com",
+			"test_type":                      "HTTP",
+			"paused":                         "false",
+			"adopt_existing":                 "false",
+			"enable_ssl_alert":               "false",
+			"on_destroy":                     "delete",
+			"wait_timeout":                   "5m",
+			"resolved_node_locations.#":      "0",
+			"tags_all.#":                     "0",
+			"check_rate":                     "120",
+			"node_locations.#":               "1",
+			"node_locations.1457391412":      "UK1",
+			"applied_defaults.%":             "3",
+			"applied_defaults.timeout":       "40",
+			"applied_defaults.trigger_rate":  "5",
+			"applied_defaults.confirmations": "0",
+		},
+	}
+
+	// Removing check_rate and node_locations from the configuration resets
+	// them, the first to its default.
+	diff := diffTestDefaults(t, defaultsTestMeta(nil), state, config)
+	if diff == nil {
+		t.Fatalf("expected removing check_rate and node_locations to plan a change")
+	}
+	if attr := diff.Attributes["applied_defaults.check_rate"]; attr == nil || attr.New != "300" {
+		t.Fatalf("expected check_rate to go back to its default, got %#v", attr)
+	}
+	if attr := diff.Attributes["check_rate"]; attr == nil || !attr.NewRemoved {
+		t.Fatalf("expected check_rate to be removed from the attribute, got %#v", attr)
+	}
+	if attr := diff.Attributes["node_locations.#"]; attr == nil || attr.New != "0" {
+		t.Fatalf("expected node_locations to be cleared, got %#v", attr)
+	}
+
+	// With nothing removed, the defaults in state plan nothing.
+	state.Attributes["applied_defaults.%"] = "4"
+	state.Attributes["applied_defaults.check_rate"] = "300"
+	delete(state.Attributes, "check_rate")
+	state.Attributes["node_locations.#"] = "0"
+	delete(state.Attributes, "node_locations.1457391412")
+	if diff := diffTestDefaults(t, defaultsTestMeta(nil), state, config); diff != nil && !diff.Empty() {
+		t.Fatalf("expected no changes, got %#v", diff.Attributes)
+	}
+
+	// Changing the provider defaults reaches existing tests.
+	diff = diffTestDefaults(t, defaultsTestMeta(testDefaults{"HTTP": {"check_rate": 60}}), state, config)
+	if diff == nil {
+		t.Fatalf("expected a change of the provider defaults to plan an update")
+	}
+	if attr := diff.Attributes["applied_defaults.check_rate"]; attr == nil || attr.Old != "300" || attr.New != "60" {
+		t.Fatalf("expected check_rate to follow the provider defaults, got %#v", attr)
+	}
+
+	// Setting an attribute in the configuration takes it out of the defaults.
+	config["check_rate"] = 300
+	diff = diffTestDefaults(t, defaultsTestMeta(nil), state, config)
+	if attr := diff.Attributes["check_rate"]; attr == nil || attr.New != "300" {
+		t.Fatalf("expected check_rate to be set from the configuration, got %#v", attr)
+	}
+	if attr := diff.Attributes["applied_defaults.check_rate"]; attr == nil || !attr.NewRemoved {
+		t.Fatalf("expected check_rate to leave applied_defaults, got %#v", attr)
+	}
+}
+
+func TestUpgradeTestStateV0(t *testing.T) {
+	// A test created before applied_defaults, with a custom timeout
+	rawState := map[string]interface{}{
+		"id":               "1",
+		"test_id":          "1",
+		"website_name":     "example",
+		"website_url":      "example.com",
+		"test_type":        "HTTP",
+		"paused":           false,
+		"adopt_existing":   false,
+		"enable_ssl_alert": false,
+		"on_destroy":       "delete",
+		"wait_timeout":     "5m",
+		"check_rate":       float64(300),
+		"timeout":          float64(60),
+		"trigger_rate":     float64(5),
+		"confirmations":    float64(0),
+		"contact_group":    []interface{}{"1"},
+		"node_locations":   []interface{}{},
+		"tags_all":         []interface{}{},
+	}
+
+	upgraded, err := upgradeTestStateV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{"check_rate": "300", "trigger_rate": "5", "confirmations": "0"}
+	if !reflect.DeepEqual(upgraded["applied_defaults"], expected) {
+		t.Fatalf("expected applied_defaults %v, got %v", expected, upgraded["applied_defaults"])
+	}
+	for _, key := range []string{"check_rate", "trigger_rate", "confirmations", "node_locations"} {
+		if v, ok := upgraded[key]; ok {
+			t.Errorf("expected %s to be moved out of the attributes, got %v", key, v)
+		}
+	}
+	if upgraded["timeout"] != float64(60) || len(upgraded["contact_group"].([]interface{})) != 1 {
+		t.Errorf("expected the configured values to stay, got %v", upgraded)
+	}
+
+	// The upgraded state plans nothing for the configuration it came from
+	r := resourceStatusCakeTest()
+	b, err := json.Marshal(upgraded)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	val, err := ctyjson.Unmarshal(b, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.ShimInstanceStateFromValue(val)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff := diffTestDefaults(t, defaultsTestMeta(nil), state, map[string]interface{}{
+		"website_name":  "example",
+		"website_url":   "example.com",
+		"test_type":     "HTTP",
+		"timeout":       60,
+		"contact_group": []interface{}{"1"},
+	})
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no changes after the upgrade, got %#v", diff.Attributes)
+	}
+}
+
+func defaultsTestMeta(defaults testDefaults) *StatusCakeClient {
+	return &StatusCakeClient{providerState: &providerState{
+		testDefaults:  defaults,
+		contactGroups: map[int]*statuscake.ContactGroup{1: {ContactID: 1}, 2: {ContactID: 2}},
+		locations:     []*statuscake.Location{{ServerCode: "UK1"}},
+	}}
+}
+
+func diffTestDefaults(t *testing.T, meta *StatusCakeClient, state *terraform.InstanceState, c map[string]interface{}) *terraform.InstanceDiff {
+	raw, err := config.NewRawConfig(c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	diff, err := resourceStatusCakeTest().Diff(state, terraform.NewResourceConfig(raw), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return diff
+}
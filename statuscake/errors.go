@@ -0,0 +1,158 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+// testAPIFields maps the API field names of a test to statuscake_test
+// attributes.
+var testAPIFields = map[string]string{
+	"WebsiteName":    "website_name",
+	"WebsiteURL":     "website_url",
+	"TestType":       "test_type",
+	"CheckRate":      "check_rate",
+	"ContactGroup":   "contact_group",
+	"ContactID":      "contact_id",
+	"Paused":         "paused",
+	"Timeout":        "timeout",
+	"Confirmation":   "confirmations",
+	"TriggerRate":    "trigger_rate",
+	"Port":           "port",
+	"CustomHeader":   "custom_header",
+	"UserAgent":      "user_agent",
+	"NodeLocations":  "node_locations",
+	"PingURL":        "ping_url",
+	"BasicUser":      "basic_user",
+	"BasicPass":      "basic_pass",
+	"Public":         "public",
+	"LogoImage":      "logo_image",
+	"Branding":       "branding",
+	"WebsiteHost":    "website_host",
+	"Virus":          "virus",
+	"FindString":     "find_string",
+	"DoNotFind":      "do_not_find",
+	"RealBrowser":    "real_browser",
+	"TestTags":       "test_tags",
+	"StatusCodes":    "status_codes",
+	"UseJar":         "use_jar",
+	"PostRaw":        "post_raw",
+	"FinalEndpoint":  "final_endpoint",
+	"EnableSSLAlert": "enable_ssl_alert",
+	"FollowRedirect": "follow_redirect",
+}
+
+// contactGroupAPIFields maps the API field names of a contact group to
+// statuscake_contact_group attributes.
+var contactGroupAPIFields = map[string]string{
+	"GroupName":    "group_name",
+	"Email":        "emails",
+	"Emails":       "emails",
+	"Mobile":       "mobiles",
+	"Mobiles":      "mobiles",
+	"Boxcar":       "boxcar",
+	"Pushover":     "pushover",
+	"PingURL":      "ping_url",
+	"DesktopAlert": "desktop_alert",
+}
+
+// apiError is an API error reworded for the user. It still unwraps to the
+// error returned by the client.
+type apiError struct {
+	message string
+	err     error
+}
+
+func (e *apiError) Error() string { return e.message }
+
+func (e *apiError) Unwrap() error { return e.err }
+
+// describeAPIError rewords an API error in terms of the resource's
+// attributes, so problems the API reports against a field name such as
+// CheckRate point at the attribute to change, such as check_rate.
+func describeAPIError(err error, fields map[string]string) error {
+	var validation statuscake.ValidationError
+	var update *statuscake.UpdateError
+	var auth *statuscake.AuthenticationError
+
+	switch {
+	case errors.As(err, &validation):
+		return &apiError{message: describeIssues(validation, fields), err: err}
+	case errors.As(err, &update):
+		var messages []string
+		if update.Message != "" {
+			messages = append(messages, update.Message)
+		}
+		if len(update.Issues) > 0 {
+			messages = append(messages, describeIssues(update.Issues, fields))
+		}
+		messages = append(messages, update.Details...)
+		return &apiError{message: strings.Join(messages, ": "), err: err}
+	case errors.As(err, &auth):
+		return &apiError{message: fmt.Sprintf("authentication failed, check username and apikey: %s", auth.Message), err: err}
+	}
+
+	return err
+}
+
+func describeIssues(issues statuscake.ValidationError, fields map[string]string) string {
+	messages := make([]string, 0, len(issues))
+	for _, field := range issues.Fields() {
+		name := field
+		if attr, ok := fields[field]; ok {
+			name = attr
+		}
+		messages = append(messages, fmt.Sprintf("%s %s", name, issues[field]))
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// describeCredentialError explains why the credentials were rejected when
+// the provider is configured, telling a wrong username, a wrong key and a
+// locked account apart from the message of the API.
+func describeCredentialError(err error) error {
+	var auth *statuscake.AuthenticationError
+	var httpErr *statuscake.HTTPError
+	var syntax *json.SyntaxError
+
+	var reason string
+	switch {
+	case errors.As(err, &auth):
+		message := strings.ToLower(auth.Message)
+		switch {
+		case strings.Contains(message, "lock") || strings.Contains(message, "suspend") || strings.Contains(message, "disabled"):
+			reason = "the StatusCake account is locked, contact StatusCake support"
+		case strings.Contains(message, "user") && !strings.Contains(message, "key"):
+			reason = "the username is wrong"
+		case strings.Contains(message, "key"):
+			reason = "the apikey is wrong or does not belong to the username"
+		default:
+			reason = "the username or the apikey is wrong"
+		}
+		if auth.Message != "" {
+			reason = fmt.Sprintf("%s (StatusCake says: %s)", reason, auth.Message)
+		}
+	case errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden):
+		reason = fmt.Sprintf("the credentials were rejected with %s", httpErr.Status)
+	case errors.As(err, &syntax):
+		reason = "the response is not the JSON of the StatusCake API, check proxy_url and the network"
+	default:
+		return fmt.Errorf("Error validating StatusCake credentials: %s", err)
+	}
+
+	return &apiError{
+		message: fmt.Sprintf("Error validating StatusCake credentials: %s. Set skip_credentials_validation to skip this check.", reason),
+		err:     err,
+	}
+}
+
+func isNotFound(err error) bool {
+	var notFound *statuscake.NotFoundError
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,64 @@
+package statuscake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func TestDescribeAPIError(t *testing.T) {
+	update := &statuscake.UpdateError{
+		Message: "Invalid test",
+		Issues:  statuscake.ValidationError{"CheckRate": "must be between 0 and 23999", "Unknown": "is wrong"},
+	}
+
+	err := describeAPIError(update, testAPIFields)
+	expected := "Invalid test: check_rate must be between 0 and 23999, Unknown is wrong"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	var unwrapped *statuscake.UpdateError
+	if !errors.As(fmt.Errorf("Error creating StatusCake Test: %w", err), &unwrapped) {
+		t.Fatal("expected the reworded error to unwrap to the client error")
+	}
+
+	err = describeAPIError(statuscake.ValidationError{"Email": "is invalid"}, contactGroupAPIFields)
+	if err.Error() != "emails is invalid" {
+		t.Fatalf("expected emails is invalid, got %q", err.Error())
+	}
+
+	other := fmt.Errorf("boom")
+	if describeAPIError(other, testAPIFields) != other {
+		t.Fatal("expected other errors to be returned as they are")
+	}
+}
+
+func TestDescribeCredentialError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{&statuscake.AuthenticationError{Message: "API Key does not match the Username"}, "the apikey is wrong"},
+		{&statuscake.AuthenticationError{Message: "No user found"}, "the username is wrong"},
+		{&statuscake.AuthenticationError{Message: "Account Locked"}, "the StatusCake account is locked"},
+		{&statuscake.AuthenticationError{Message: "Authentication Failed"}, "the username or the apikey is wrong"},
+		{&statuscake.HTTPError{StatusCode: 401, Status: "401 Unauthorized"}, "rejected with 401 Unauthorized"},
+		{fmt.Errorf("Error decoding: %w", &json.SyntaxError{}), "not the JSON of the StatusCake API"},
+	}
+
+	for _, tc := range cases {
+		err := describeCredentialError(tc.err)
+		if !strings.Contains(err.Error(), tc.expected) || !strings.Contains(err.Error(), "skip_credentials_validation") {
+			t.Errorf("expected %q to explain %q, got %q", tc.err, tc.expected, err)
+		}
+	}
+
+	if err := describeCredentialError(fmt.Errorf("connection refused")); !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected other errors to be kept, got %q", err)
+	}
+}
@@ -0,0 +1,132 @@
+package statuscake
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// allowMassDeleteEnv lifts max_deletes_per_run for a single run.
+const allowMassDeleteEnv = "STATUSCAKE_ALLOW_MASS_DELETE"
+
+type writeFunc func(*schema.ResourceData, interface{}) error
+
+// guardWrites wraps the Create, Update and Delete functions of a resource so
+// they fail fast when the provider is read only, and so deletes are counted
+// against max_deletes_per_run. Read is left untouched.
+func guardWrites(name string, r *schema.Resource) {
+	if r.Create != nil {
+		r.Create = schema.CreateFunc(guardWrite(name, "create", writeFunc(r.Create)))
+	}
+	if r.Update != nil {
+		r.Update = schema.UpdateFunc(guardWrite(name, "update", writeFunc(r.Update)))
+	}
+	if r.Delete != nil {
+		r.Delete = schema.DeleteFunc(guardWrite(name, "delete", guardDelete(name, r, writeFunc(r.Delete))))
+	}
+}
+
+func guardWrite(name, op string, f writeFunc) writeFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		if meta.(*StatusCakeClient).readOnly {
+			target := name
+			if d.Id() != "" {
+				target = fmt.Sprintf("%s %s", name, d.Id())
+			}
+			return fmt.Errorf("Refusing to %s %s: the StatusCake provider is configured with read_only", op, target)
+		}
+
+		return f(d, meta)
+	}
+}
+
+func guardDelete(name string, r *schema.Resource, f writeFunc) writeFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		// Destroying a resource that is only paused does not delete anything
+		if _, ok := r.Schema["on_destroy"]; ok && d.Get("on_destroy").(string) != onDestroyDelete {
+			return f(d, meta)
+		}
+
+		client := meta.(*StatusCakeClient)
+		target := describeResource(name, r, d)
+
+		if err := client.deletes.reserve(client.maxDeletes, target); err != nil {
+			return err
+		}
+		if err := f(d, meta); err != nil {
+			client.deletes.release(target)
+			return err
+		}
+
+		return nil
+	}
+}
+
+// describeResource names a resource for delete budget errors, including its
+// human readable name when the schema has one.
+func describeResource(name string, r *schema.Resource, d *schema.ResourceData) string {
+	target := fmt.Sprintf("%s %s", name, d.Id())
+	for _, key := range []string{"website_name", "group_name"} {
+		if _, ok := r.Schema[key]; !ok {
+			continue
+		}
+		if v, ok := d.GetOk(key); ok {
+			return fmt.Sprintf("%s (%q)", target, v)
+		}
+	}
+
+	return target
+}
+
+// processDeletes counts deletes across every StatusCake resource in this
+// provider process, whichever provider configuration they belong to.
+var processDeletes = &deleteLedger{}
+
+// deleteLedger records the deletes made against a budget during a run.
+type deleteLedger struct {
+	mu      sync.Mutex
+	deleted []string
+	refused []string
+}
+
+// reserve counts target against budget, failing once the budget is used up
+// unless the override environment variable is set. A budget of 0 or less
+// means no limit.
+func (l *deleteLedger) reserve(budget int, target string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if budget <= 0 || len(l.deleted) < budget || allowMassDelete() {
+		l.deleted = append(l.deleted, target)
+		return nil
+	}
+
+	l.refused = append(l.refused, target)
+
+	return fmt.Errorf("Refusing to delete %s: max_deletes_per_run is %d and this run already deleted %s.\n"+
+		"Deletes refused so far: %s.\n"+
+		"Check the plan, then set %s=true to allow the deletes.",
+		target, budget, strings.Join(l.deleted, ", "), strings.Join(l.refused, ", "), allowMassDeleteEnv)
+}
+
+// release gives back the budget reserved for a delete that failed.
+func (l *deleteLedger) release(target string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, v := range l.deleted {
+		if v == target {
+			l.deleted = append(l.deleted[:i], l.deleted[i+1:]...)
+			return
+		}
+	}
+}
+
+func allowMassDelete() bool {
+	allow, _ := strconv.ParseBool(os.Getenv(allowMassDeleteEnv))
+	return allow
+}
@@ -0,0 +1,113 @@
+package statuscake
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestGuardWrites_readOnly(t *testing.T) {
+	called := false
+	write := func(d *schema.ResourceData, meta interface{}) error {
+		called = true
+		return nil
+	}
+	r := &schema.Resource{
+		Create: write,
+		Read:   write,
+		Update: write,
+		Delete: write,
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+		},
+	}
+	guardWrites("statuscake_example", r)
+
+	meta := &StatusCakeClient{providerState: &providerState{readOnly: true}}
+	d := r.TestResourceData()
+	d.SetId("42")
+
+	for op, f := range map[string]func(*schema.ResourceData, interface{}) error{
+		"create": r.Create,
+		"update": r.Update,
+		"delete": r.Delete,
+	} {
+		err := f(d, meta)
+		if err == nil || !strings.Contains(err.Error(), "Refusing to "+op+" statuscake_example 42") {
+			t.Fatalf("expected %s to be refused, got %v", op, err)
+		}
+	}
+	if called {
+		t.Fatalf("expected no write to reach the resource")
+	}
+
+	if err := r.Read(d, meta); err != nil || !called {
+		t.Fatalf("expected reads to keep working, got %v", err)
+	}
+
+	called = false
+	if err := r.Delete(d, &StatusCakeClient{providerState: &providerState{deletes: &deleteLedger{}}}); err != nil || !called {
+		t.Fatalf("expected writes to go through when not read only, got %v", err)
+	}
+}
+
+func TestGuardWrites_deleteBudget(t *testing.T) {
+	failing := map[string]bool{"3": true}
+	r := &schema.Resource{
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			if failing[d.Id()] {
+				return fmt.Errorf("API error")
+			}
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"website_name": {Type: schema.TypeString, Optional: true},
+		},
+	}
+	guardWrites("statuscake_test", r)
+
+	meta := &StatusCakeClient{providerState: &providerState{maxDeletes: 2, deletes: &deleteLedger{}}}
+	del := func(id string) error {
+		d := r.TestResourceData()
+		d.SetId(id)
+		d.Set("website_name", "site "+id)
+		return r.Delete(d, meta)
+	}
+
+	if err := del("1"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := del("3"); err == nil {
+		t.Fatalf("expected the API error")
+	}
+	if err := del("2"); err != nil {
+		t.Fatalf("expected a failed delete not to use up the budget, got %s", err)
+	}
+
+	err := del("4")
+	if err == nil {
+		t.Fatalf("expected the delete budget to be exhausted")
+	}
+	for _, want := range []string{`statuscake_test 4 ("site 4")`, `statuscake_test 1 ("site 1")`, `statuscake_test 2 ("site 2")`, allowMassDeleteEnv} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected the error to mention %s, got: %s", want, err)
+		}
+	}
+
+	r.Schema["on_destroy"] = &schema.Schema{Type: schema.TypeString, Optional: true, Default: onDestroyDelete}
+	d := r.TestResourceData()
+	d.SetId("6")
+	d.Set("on_destroy", onDestroyPause)
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("expected pausing not to count as a delete, got %s", err)
+	}
+
+	os.Setenv(allowMassDeleteEnv, "true")
+	defer os.Unsetenv(allowMassDeleteEnv)
+	if err := del("5"); err != nil {
+		t.Fatalf("expected %s to lift the budget, got %s", allowMassDeleteEnv, err)
+	}
+}
@@ -0,0 +1,111 @@
+package statuscake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+// locationRegions lists the regions accepted by location_selector.
+var locationRegions = []string{
+	"africa",
+	"asia",
+	"europe",
+	"north_america",
+	"oceania",
+	"south_america",
+}
+
+// countryRegions maps ISO 3166-1 alpha-2 country codes to the region they
+// belong to. The API only reports a node's country, so regions are derived.
+var countryRegions = map[string]string{
+	// Africa
+	"DZ": "africa", "EG": "africa", "GH": "africa", "KE": "africa", "MA": "africa",
+	"MU": "africa", "NG": "africa", "TN": "africa", "ZA": "africa",
+
+	// Asia, including the Middle East
+	"AE": "asia", "BD": "asia", "CN": "asia", "HK": "asia", "ID": "asia",
+	"IL": "asia", "IN": "asia", "JP": "asia", "KR": "asia", "KZ": "asia",
+	"MY": "asia", "PH": "asia", "PK": "asia", "QA": "asia", "SA": "asia",
+	"SG": "asia", "TH": "asia", "TR": "asia", "TW": "asia", "VN": "asia",
+
+	// Europe
+	"AT": "europe", "BE": "europe", "BG": "europe", "CH": "europe", "CY": "europe",
+	"CZ": "europe", "DE": "europe", "DK": "europe", "EE": "europe", "ES": "europe",
+	"FI": "europe", "FR": "europe", "GB": "europe", "GR": "europe", "HR": "europe",
+	"HU": "europe", "IE": "europe", "IS": "europe", "IT": "europe", "LT": "europe",
+	"LU": "europe", "LV": "europe", "MD": "europe", "MT": "europe", "NL": "europe",
+	"NO": "europe", "PL": "europe", "PT": "europe", "RO": "europe", "RS": "europe",
+	"RU": "europe", "SE": "europe", "SI": "europe", "SK": "europe", "UA": "europe",
+
+	// North America, including Central America and the Caribbean
+	"CA": "north_america", "CR": "north_america", "MX": "north_america",
+	"PA": "north_america", "PR": "north_america", "US": "north_america",
+
+	// Oceania
+	"AU": "oceania", "FJ": "oceania", "NZ": "oceania",
+
+	// South America
+	"AR": "south_america", "BR": "south_america", "CL": "south_america",
+	"CO": "south_america", "EC": "south_america", "PE": "south_america",
+	"UY": "south_america", "VE": "south_america",
+}
+
+// locationSelector picks node locations by region and country instead of by
+// server code.
+type locationSelector struct {
+	Regions   []string
+	Countries []string
+	MinCount  int
+}
+
+func (s *locationSelector) matches(l *statuscake.Location) bool {
+	country := strings.ToUpper(l.CountryISO)
+
+	if len(s.Regions) > 0 && !containsString(s.Regions, countryRegions[country]) {
+		return false
+	}
+	if len(s.Countries) > 0 && !containsString(s.Countries, country) {
+		return false
+	}
+
+	return true
+}
+
+// resolve returns the sorted server codes of every available node matching
+// the selector, failing when fewer than MinCount nodes match.
+func (s *locationSelector) resolve(locations []*statuscake.Location) ([]string, error) {
+	if len(s.Regions) == 0 && len(s.Countries) == 0 {
+		return nil, fmt.Errorf("location_selector: at least one of regions or countries must be set")
+	}
+
+	var codes []string
+	for _, l := range locations {
+		if !strings.EqualFold(l.Status, "Up") || l.ServerCode == "" {
+			continue
+		}
+		if s.matches(l) {
+			codes = append(codes, l.ServerCode)
+		}
+	}
+	sort.Strings(codes)
+
+	if len(codes) < s.MinCount {
+		return nil, fmt.Errorf("location_selector: %d node locations match regions %v and countries %v, fewer than min_count %d",
+			len(codes), s.Regions, s.Countries, s.MinCount)
+	}
+
+	return codes, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
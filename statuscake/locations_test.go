@@ -0,0 +1,70 @@
+package statuscake
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+)
+
+func TestLocationSelectorResolve(t *testing.T) {
+	locations := []*statuscake.Location{
+		{ServerCode: "UK2", CountryISO: "GB", Status: "Up"},
+		{ServerCode: "UK1", CountryISO: "GB", Status: "Up"},
+		{ServerCode: "UK3", CountryISO: "GB", Status: "Down"},
+		{ServerCode: "DE1", CountryISO: "DE", Status: "Up"},
+		{ServerCode: "US1", CountryISO: "US", Status: "Up"},
+		{ServerCode: "AU1", CountryISO: "au", Status: "Up"},
+	}
+
+	cases := []struct {
+		name     string
+		selector locationSelector
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "by country",
+			selector: locationSelector{Countries: []string{"GB"}, MinCount: 1},
+			expected: []string{"UK1", "UK2"},
+		},
+		{
+			name:     "by region",
+			selector: locationSelector{Regions: []string{"europe", "oceania"}, MinCount: 1},
+			expected: []string{"AU1", "DE1", "UK1", "UK2"},
+		},
+		{
+			name:     "region and country",
+			selector: locationSelector{Regions: []string{"europe"}, Countries: []string{"DE", "US"}, MinCount: 1},
+			expected: []string{"DE1"},
+		},
+		{
+			name:     "too few matches",
+			selector: locationSelector{Countries: []string{"GB"}, MinCount: 3},
+			err:      true,
+		},
+		{
+			name:     "empty selector",
+			selector: locationSelector{MinCount: 1},
+			err:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			codes, err := tc.selector.resolve(locations)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", codes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(codes, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, codes)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package statuscake
 
 import (
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -39,3 +41,31 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("STATUSCAKE_TEST_CONTACT_GROUP_ID must be set for acceptance tests")
 	}
 }
+
+func TestProviderTransport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"proxy_url": "http://proxy.example.com:3128",
+	})
+	transport, err := providerTransport(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req, _ := http.NewRequest("GET", "https://app.statuscake.com/API/Tests", nil)
+	if proxy, _ := transport.Proxy(req); proxy == nil || proxy.Host != "proxy.example.com:3128" {
+		t.Errorf("expected requests to go through proxy_url, got %v", proxy)
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"ca_bundle_file": "testdata/missing.pem",
+	})
+	if _, err := providerTransport(d); err == nil || !strings.Contains(err.Error(), "ca_bundle_file") {
+		t.Errorf("expected an error naming ca_bundle_file, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"client_certificate": "-----BEGIN CERTIFICATE-----",
+	})
+	if _, err := providerTransport(d); err == nil {
+		t.Error("expected an error for a client certificate without a key")
+	}
+}
@@ -0,0 +1,132 @@
+package statuscake
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
+)
+
+// TestResources_concurrent runs the resource CRUD functions in parallel
+// against a stand-in server, sharing one provider client and its caches the
+// way Terraform does. Run it with -race.
+func TestResources_concurrent(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	c, err := statuscake.New(statuscake.Auth{Username: statuscaketest.Username, Apikey: statuscaketest.Apikey},
+		statuscake.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &StatusCakeClient{Client: c, providerState: &providerState{deletes: &deleteLedger{}}}
+
+	const workers = 16
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			exerciseResources(t, client, i)
+		}(i)
+	}
+	wg.Wait()
+
+	if n := srv.Requests("GET /Tests"); n != 1 {
+		t.Errorf("expected one shared test list request, got %d", n)
+	}
+}
+
+// TestResources_v1 runs the same operations against the v1 REST API, which
+// the resources must handle exactly like the legacy API.
+func TestResources_v1(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	c, err := statuscake.New(statuscake.Auth{Token: statuscaketest.Token},
+		statuscake.WithAPIVersion(statuscake.APIVersionV1), statuscake.WithBaseURL(srv.URL+"/v1"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &StatusCakeClient{Client: c, providerState: &providerState{deletes: &deleteLedger{}}}
+
+	const workers = 4
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			exerciseResources(t, client, i)
+		}(i)
+	}
+	wg.Wait()
+
+	if n := srv.Requests("GET /Tests") + srv.Requests("GET /ContactGroups"); n != 0 {
+		t.Errorf("expected no legacy API requests, got %d", n)
+	}
+	if n := srv.Requests("GET /v1/uptime"); n != 1 {
+		t.Errorf("expected one shared test list request, got %d", n)
+	}
+}
+
+func exerciseResources(t *testing.T, client *StatusCakeClient, i int) {
+	name := fmt.Sprintf("test-%d", i)
+
+	group := schema.TestResourceDataRaw(t, resourceStatusCakeContactGroup().Schema, map[string]interface{}{
+		"group_name": name,
+		"emails":     []interface{}{name + "@example.com"},
+	})
+	if err := CreateContactGroup(group, client); err != nil {
+		t.Errorf("%s: create contact group: %s", name, err)
+		return
+	}
+
+	test := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, map[string]interface{}{
+		"website_name":  name,
+		"website_url":   "https://example.com",
+		"test_type":     "HTTP",
+		"check_rate":    300,
+		"contact_group": []interface{}{group.Id()},
+		"on_destroy":    onDestroyDelete,
+	})
+	if err := CreateTest(test, client); err != nil {
+		t.Errorf("%s: create: %s", name, err)
+		return
+	}
+
+	if _, err := client.contactGroupIDs(false); err != nil {
+		t.Errorf("%s: contact group IDs: %s", name, err)
+	}
+	if _, err := client.nodeLocationCodes(false); err != nil {
+		t.Errorf("%s: node locations: %s", name, err)
+	}
+
+	test.Set("check_rate", 60)
+	if err := UpdateTest(test, client); err != nil {
+		t.Errorf("%s: update: %s", name, err)
+	}
+	if err := ReadTest(test, client); err != nil {
+		t.Errorf("%s: read: %s", name, err)
+	}
+	if v := test.Get("check_rate").(int); v != 60 {
+		t.Errorf("%s: expected check_rate 60 after update, got %d", name, v)
+	}
+
+	group.Set("group_name", name+"-renamed")
+	if err := UpdateContactGroup(group, client); err != nil {
+		t.Errorf("%s: update contact group: %s", name, err)
+	}
+	if err := ReadContactGroup(group, client); err != nil {
+		t.Errorf("%s: read contact group: %s", name, err)
+	}
+
+	if err := DeleteTest(test, client); err != nil {
+		t.Errorf("%s: delete: %s", name, err)
+	}
+	if err := DeleteContactGroup(group, client); err != nil {
+		t.Errorf("%s: delete contact group: %s", name, err)
+	}
+}
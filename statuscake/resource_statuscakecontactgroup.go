@@ -2,9 +2,10 @@ package statuscake
 
 import (
 	"fmt"
+	"time"
 
-	"github.com/DreamItGetIT/statuscake"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
 	"log"
 	"strconv"
 )
@@ -18,6 +19,7 @@ func resourceStatusCakeContactGroup() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: defaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"contact_id": {
@@ -58,7 +60,10 @@ func resourceStatusCakeContactGroup() *schema.Resource {
 }
 
 func CreateContactGroup(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
+	if err := checkAPISupport(client, d, v1UnsupportedContactGroupAttributes); err != nil {
+		return err
+	}
 
 	newContactGroup := &statuscake.ContactGroup{
 		GroupName:    d.Get("group_name").(string),
@@ -72,10 +77,11 @@ func CreateContactGroup(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] Creating new StatusCake Contact group: %s", d.Get("group_name").(string))
 
-	response, err := statuscake.NewContactGroups(client).Create(newContactGroup)
+	response, err := client.ContactGroups().Create(newContactGroup)
 	if err != nil {
-		return fmt.Errorf("Error creating StatusCake ContactGroup: %s", err.Error())
+		return fmt.Errorf("Error creating StatusCake ContactGroup: %w", describeAPIError(err, contactGroupAPIFields))
 	}
+	client.invalidateContactGroups()
 
 	d.Set("mobiles", newContactGroup.Mobiles)
 	d.Set("boxcar", newContactGroup.Boxcar)
@@ -84,11 +90,15 @@ func CreateContactGroup(d *schema.ResourceData, meta interface{}) error {
 	d.Set("contact_id", newContactGroup.ContactID)
 	d.SetId(strconv.Itoa(response.ContactID))
 
-	return ReadContactGroup(d, meta)
+	newContactGroup.ContactID = response.ContactID
+	return readContactGroupAfterWrite(d, client, newContactGroup, d.Timeout(schema.TimeoutCreate))
 }
 
 func UpdateContactGroup(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
+	if err := checkAPISupport(client, d, v1UnsupportedContactGroupAttributes); err != nil {
+		return err
+	}
 
 	params := &statuscake.ContactGroup{
 		GroupName:    d.Get("group_name").(string),
@@ -101,38 +111,93 @@ func UpdateContactGroup(d *schema.ResourceData, meta interface{}) error {
 		PingURL:      d.Get("ping_url").(string),
 	}
 	log.Printf("[DEBUG] StatusCake ContactGroup Update for %s", d.Id())
-	_, err := statuscake.NewContactGroups(client).Update(params)
+	_, err := client.ContactGroups().Update(params)
+	client.invalidateContactGroups()
 	d.Set("mobiles", params.Mobiles)
 	d.Set("boxcar", params.Boxcar)
 	d.Set("pushover", params.Pushover)
 	d.Set("desktop_alert", params.DesktopAlert)
 	if err != nil {
-		return fmt.Errorf("Error Updating StatusCake ContactGroup: %s", err.Error())
+		return fmt.Errorf("Error Updating StatusCake ContactGroup: %w", describeAPIError(err, contactGroupAPIFields))
 	}
-	return ReadContactGroup(d, meta)
+	return readContactGroupAfterWrite(d, client, params, d.Timeout(schema.TimeoutUpdate))
+}
+
+// readContactGroupAfterWrite waits for the API to reflect the contact group
+// just written and then updates the state from it.
+func readContactGroupAfterWrite(d *schema.ResourceData, client *StatusCakeClient, written *statuscake.ContactGroup, timeout time.Duration) error {
+	var response *statuscake.ContactGroup
+	err := retryRead(client, timeout, fmt.Sprintf("StatusCake ContactGroup %d", written.ContactID), func() (string, error) {
+		var err error
+		response, err = client.contactGroup(written.ContactID, true)
+		if err != nil {
+			return "", err
+		}
+		return staleContactGroupField(written, response), nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error waiting for StatusCake ContactGroup %d: %s", written.ContactID, err)
+	}
+
+	setContactGroupState(d, response)
+	return nil
 }
 
 func DeleteContactGroup(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
 	id, _ := strconv.Atoi(d.Id())
 	log.Printf("[DEBUG] Deleting StatusCake ContactGroup: %s", d.Id())
-	err := statuscake.NewContactGroups(client).Delete(id)
+	err := client.ContactGroups().Delete(id)
+	client.invalidateContactGroups()
+	if err != nil {
+		return err
+	}
 
-	return err
+	return retryRead(client, d.Timeout(schema.TimeoutDelete), fmt.Sprintf("StatusCake ContactGroup %d", id), func() (string, error) {
+		_, err := client.contactGroup(id, true)
+		if isNotFound(err) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return "the contact group still exists", nil
+	})
 }
 
 func ReadContactGroup(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
 	id, _ := strconv.Atoi(d.Id())
-	response, err := statuscake.NewContactGroups(client).Detail(id)
+	var response *statuscake.ContactGroup
+	var gone bool
+	refresh := false
+	err := retryRead(client, d.Timeout(schema.TimeoutRead), fmt.Sprintf("StatusCake ContactGroup %d", id), func() (string, error) {
+		var err error
+		response, err = client.contactGroup(id, refresh)
+		refresh = true
+		gone = isNotFound(err)
+		if gone {
+			return "", nil
+		}
+		return "", err
+	})
 	if err != nil {
 		return fmt.Errorf("Error Getting StatusCake ContactGroup Details for %s: Error: %s", d.Id(), err)
 	}
+	if gone {
+		log.Printf("[WARN] StatusCake ContactGroup %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	setContactGroupState(d, response)
+
+	return nil
+}
+
+func setContactGroupState(d *schema.ResourceData, response *statuscake.ContactGroup) {
 	d.Set("group_name", response.GroupName)
 	d.Set("emails", response.Emails)
 	d.Set("contact_id", response.ContactID)
 	d.Set("ping_url", response.PingURL)
 	d.SetId(strconv.Itoa(response.ContactID))
-
-	return nil
 }
@@ -2,9 +2,9 @@ package statuscake
 
 import (
 	"fmt"
-	"github.com/DreamItGetIT/statuscake"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
 	"strconv"
 	"testing"
 )
@@ -68,10 +68,10 @@ func testAccContactGroupCheckExists(rn string, contactGroup *statuscake.ContactG
 			return fmt.Errorf("ContactGroupID not set")
 		}
 
-		client := testAccProvider.Meta().(*statuscake.Client)
+		client := testAccProvider.Meta().(*StatusCakeClient)
 		contactGroupId, _ := strconv.Atoi(rs.Primary.ID)
 
-		gotContactGroup, err := statuscake.NewContactGroups(client).Detail(contactGroupId)
+		gotContactGroup, err := statuscake.NewContactGroups(client.Client).Detail(contactGroupId)
 		if err != nil {
 			return fmt.Errorf("error getting ContactGroup: %s", err)
 		}
@@ -130,8 +130,8 @@ func testAccContactGroupCheckAttributes(rn string, contactGroup *statuscake.Cont
 
 func testAccContactGroupCheckDestroy(contactGroup *statuscake.ContactGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*statuscake.Client)
-		_, err := statuscake.NewContactGroups(client).Detail(contactGroup.ContactID)
+		client := testAccProvider.Meta().(*StatusCakeClient)
+		_, err := statuscake.NewContactGroups(client.Client).Detail(contactGroup.ContactID)
 		if err == nil {
 			return fmt.Errorf("contact_group still exists")
 		}
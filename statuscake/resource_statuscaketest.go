@@ -1,15 +1,45 @@
 package statuscake
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"log"
+	"net/url"
+	"time"
 
-	"github.com/DreamItGetIT/statuscake"
+	"github.com/hashicorp/terraform/helper/customdiff"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
 )
 
+// What to do with a test when its resource is destroyed
+const (
+	onDestroyDelete      = "delete"
+	onDestroyPause       = "pause"
+	onDestroyPauseAndTag = "pause_and_tag"
+)
+
+// decommissionedTag is added to tests paused with on_destroy = "pause_and_tag"
+const decommissionedTag = "decommissioned"
+
+// clientTokenTagPrefix starts the tag recording the client token of a test
+// created with adopt_existing. It is kept out of tags_all.
+const clientTokenTagPrefix = "tf-client-token-"
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration such as \"5m\": %s", k, err))
+	}
+	return
+}
+
 func castSetToSliceStrings(configured []interface{}) []string {
 	res := make([]string, len(configured))
 
@@ -29,7 +59,7 @@ func considerEmptyStringAsEmptyArray(in []string) []string {
 }
 
 func resourceStatusCakeTest() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: CreateTest,
 		Update: UpdateTest,
 		Delete: DeleteTest,
@@ -37,6 +67,15 @@ func resourceStatusCakeTest() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: defaultResourceTimeouts(),
+		CustomizeDiff: customdiff.Sequence(
+			applyTestDefaults,
+			resolveLocationSelector,
+			validateTestReferences,
+			customizeTagsAll("test_tags", "tags_all"),
+		),
+
+		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
 			"test_id": {
@@ -72,7 +111,6 @@ func resourceStatusCakeTest() *schema.Resource {
 			"check_rate": {
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  300,
 			},
 
 			"test_type": {
@@ -89,7 +127,6 @@ func resourceStatusCakeTest() *schema.Resource {
 			"timeout": {
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  40,
 			},
 
 			"confirmations": {
@@ -105,7 +142,6 @@ func resourceStatusCakeTest() *schema.Resource {
 			"trigger_rate": {
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  5,
 			},
 
 			"custom_header": {
@@ -129,9 +165,54 @@ func resourceStatusCakeTest() *schema.Resource {
 			},
 
 			"node_locations": {
+				Type:          schema.TypeSet,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Optional:      true,
+				Set:           schema.HashString,
+				ConflictsWith: []string{"location_selector"},
+			},
+
+			"location_selector": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"node_locations"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"regions": {
+							Type: schema.TypeSet,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(locationRegions, false),
+							},
+							Optional: true,
+							Set:      schema.HashString,
+						},
+
+						"countries": {
+							Type: schema.TypeSet,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Z]{2}$`), "must be an upper case ISO 3166-1 alpha-2 country code"),
+							},
+							Optional: true,
+							Set:      schema.HashString,
+						},
+
+						"min_count": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+
+			"resolved_node_locations": {
 				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
-				Optional: true,
+				Computed: true,
 				Set:      schema.HashString,
 			},
 
@@ -198,6 +279,19 @@ func resourceStatusCakeTest() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
+			"tags_all": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+				Set:      schema.HashString,
+			},
+
+			"applied_defaults": {
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
 			"status_codes": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -228,28 +322,207 @@ func resourceStatusCakeTest() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"client_token": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"wait_for_status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Up", "Down"}, false),
+			},
+
+			"wait_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+			},
+
+			"on_destroy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      onDestroyDelete,
+				ValidateFunc: validation.StringInSlice([]string{onDestroyDelete, onDestroyPause, onDestroyPauseAndTag}, false),
+			},
+		},
+	}
+
+	// Version 0 had no applied_defaults
+	v0 := make(map[string]*schema.Schema, len(r.Schema))
+	for k, v := range r.Schema {
+		v0[k] = v
+	}
+	delete(v0, "applied_defaults")
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    (&schema.Resource{Schema: v0}).CoreConfigSchema().ImpliedType(),
+			Upgrade: upgradeTestStateV0,
 		},
 	}
+
+	return r
+}
+
+// getLocationSelector returns the configured location_selector, or nil when
+// node_locations are given directly.
+func getLocationSelector(d interface{ Get(string) interface{} }) *locationSelector {
+	blocks := d.Get("location_selector").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+
+	block := blocks[0].(map[string]interface{})
+	return &locationSelector{
+		Regions:   castSetToSliceStrings(block["regions"].(*schema.Set).List()),
+		Countries: castSetToSliceStrings(block["countries"].(*schema.Set).List()),
+		MinCount:  block["min_count"].(int),
+	}
+}
+
+// resolveLocationSelector turns location_selector into concrete node codes.
+// A previous resolution is kept until the selector changes or one of the
+// chosen nodes is retired, so plans stay stable as new nodes come online.
+func resolveLocationSelector(d *schema.ResourceDiff, meta interface{}) error {
+	client := meta.(*StatusCakeClient)
+
+	selector := getLocationSelector(d)
+	if selector == nil {
+		if d.Get("resolved_node_locations").(*schema.Set).Len() > 0 {
+			return d.SetNew("resolved_node_locations", []string{})
+		}
+		return nil
+	}
+
+	if client.APIVersion() == statuscake.APIVersionV1 {
+		return unsupportedError([]string{"location_selector"})
+	}
+	if !d.NewValueKnown("location_selector") {
+		return d.SetNewComputed("resolved_node_locations")
+	}
+
+	locations, err := client.nodeLocations(false)
+	if err != nil {
+		return fmt.Errorf("Error listing StatusCake node locations: %s", err)
+	}
+
+	previous := castSetToSliceStrings(d.Get("resolved_node_locations").(*schema.Set).List())
+	if len(previous) > 0 && !d.HasChange("location_selector") {
+		codes := make(map[string]bool, len(locations))
+		for _, l := range locations {
+			codes[l.ServerCode] = true
+		}
+
+		retired := false
+		for _, code := range previous {
+			if !codes[code] {
+				log.Printf("[DEBUG] StatusCake node location %s is no longer available, resolving location_selector again", code)
+				retired = true
+			}
+		}
+		if !retired {
+			return nil
+		}
+	}
+
+	resolved, err := selector.resolve(locations)
+	if err != nil {
+		return err
+	}
+
+	return d.SetNew("resolved_node_locations", resolved)
+}
+
+// getNodeLocations returns the node codes to send to the API, taking them
+// from the resolved selector when one is configured.
+func getNodeLocations(d *schema.ResourceData) []string {
+	if getLocationSelector(d) != nil {
+		return castSetToSliceStrings(d.Get("resolved_node_locations").(*schema.Set).List())
+	}
+
+	return testStrings(d, "node_locations")
+}
+
+// validateTestReferences fails the plan when a test refers to a contact group
+// or node location that does not exist, instead of letting the API reject or
+// silently drop it during apply.
+func validateTestReferences(d *schema.ResourceDiff, meta interface{}) error {
+	client := meta.(*StatusCakeClient)
+	isNew := d.Id() == ""
+
+	var problems []string
+
+	defaultsChanged := d.HasChange("applied_defaults")
+
+	if d.NewValueKnown("contact_group") && (isNew || defaultsChanged || d.HasChange("contact_group")) {
+		groups := plannedTestStrings(d, "contact_group")
+		missing, err := missingReferences(groups, client.contactGroupIDs)
+		if err != nil {
+			return fmt.Errorf("Error listing StatusCake contact groups: %s", err)
+		}
+		for _, id := range missing {
+			problems = append(problems, fmt.Sprintf("contact_group: contact group %q does not exist", id))
+		}
+	}
+
+	if v, ok := d.GetOk("contact_id"); ok && d.NewValueKnown("contact_id") && (isNew || d.HasChange("contact_id")) {
+		missing, err := missingReferences([]string{strconv.Itoa(v.(int))}, client.contactGroupIDs)
+		if err != nil {
+			return fmt.Errorf("Error listing StatusCake contact groups: %s", err)
+		}
+		for _, id := range missing {
+			problems = append(problems, fmt.Sprintf("contact_id: contact group %q does not exist", id))
+		}
+	}
+
+	if d.NewValueKnown("node_locations") && (isNew || defaultsChanged || d.HasChange("node_locations")) {
+		codes := considerEmptyStringAsEmptyArray(plannedTestStrings(d, "node_locations"))
+		missing, err := missingReferences(codes, client.nodeLocationCodes)
+		if err != nil {
+			return fmt.Errorf("Error listing StatusCake node locations: %s", err)
+		}
+		for _, code := range missing {
+			problems = append(problems, fmt.Sprintf("node_locations: %q is not a StatusCake node location server code", code))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("Invalid StatusCake Test references:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return nil
 }
 
 func CreateTest(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
+	if err := checkAPISupport(client, d, v1UnsupportedTestAttributes); err != nil {
+		return err
+	}
 
 	newTest := &statuscake.Test{
 		WebsiteName:    d.Get("website_name").(string),
 		WebsiteURL:     d.Get("website_url").(string),
-		CheckRate:      d.Get("check_rate").(int),
+		CheckRate:      testInt(d, "check_rate"),
 		TestType:       d.Get("test_type").(string),
 		Paused:         d.Get("paused").(bool),
-		Timeout:        d.Get("timeout").(int),
-		Confirmation:   d.Get("confirmations").(int),
+		Timeout:        testInt(d, "timeout"),
+		Confirmation:   testInt(d, "confirmations"),
 		Port:           d.Get("port").(int),
-		TriggerRate:    d.Get("trigger_rate").(int),
+		TriggerRate:    testInt(d, "trigger_rate"),
 		CustomHeader:   d.Get("custom_header").(string),
 		UserAgent:      d.Get("user_agent").(string),
 		Status:         d.Get("status").(string),
 		Uptime:         d.Get("uptime").(float64),
-		NodeLocations:  castSetToSliceStrings(d.Get("node_locations").(*schema.Set).List()),
+		NodeLocations:  getNodeLocations(d),
 		PingURL:        d.Get("ping_url").(string),
 		BasicUser:      d.Get("basic_user").(string),
 		BasicPass:      d.Get("basic_pass").(string),
@@ -261,7 +534,7 @@ func CreateTest(d *schema.ResourceData, meta interface{}) error {
 		FindString:     d.Get("find_string").(string),
 		DoNotFind:      d.Get("do_not_find").(bool),
 		RealBrowser:    d.Get("real_browser").(int),
-		TestTags:       castSetToSliceStrings(d.Get("test_tags").(*schema.Set).List()),
+		TestTags:       castSetToSliceStrings(d.Get("tags_all").(*schema.Set).List()),
 		StatusCodes:    d.Get("status_codes").(string),
 		UseJar:         d.Get("use_jar").(int),
 		PostRaw:        d.Get("post_raw").(string),
@@ -270,102 +543,466 @@ func CreateTest(d *schema.ResourceData, meta interface{}) error {
 		FollowRedirect: d.Get("follow_redirect").(bool),
 	}
 
-	if v, ok := d.GetOk("contact_group"); ok {
-		newTest.ContactGroup = castSetToSliceStrings(v.(*schema.Set).List())
+	if v := testStrings(d, "contact_group"); len(v) > 0 {
+		newTest.ContactGroup = v
 	} else if v, ok := d.GetOk("contact_id"); ok {
 		newTest.ContactID = v.(int)
 	}
 
+	var token string
+	if d.Get("adopt_existing").(bool) {
+		token = clientToken(newTest)
+		newTest.TestTags = append(newTest.TestTags, token)
+
+		existing, err := findExistingTest(client, newTest, token)
+		if err != nil {
+			return fmt.Errorf("Error looking up existing StatusCake Tests: %s", err.Error())
+		}
+		if existing != nil {
+			log.Printf("[INFO] Adopting existing StatusCake Test %d for %s", existing.TestID, newTest.WebsiteName)
+			newTest.TestID = existing.TestID
+		}
+	}
+
 	log.Printf("[DEBUG] Creating new StatusCake Test: %s", d.Get("website_name").(string))
 
 	response, err := client.Tests().Update(newTest)
+	if err != nil && token != "" && isTransient(err) {
+		// The API may have created the test before the request failed
+		if created, lookupErr := findTestByClientToken(client, token); lookupErr == nil && created != nil {
+			log.Printf("[INFO] StatusCake Test %d was created despite the error: %s", created.TestID, err)
+			response, err = created, nil
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("Error creating StatusCake Test: %s", err.Error())
+		return fmt.Errorf("Error creating StatusCake Test: %w", describeAPIError(err, testAPIFields))
+	}
+
+	testID := response.TestID
+	if testID == 0 {
+		testID = newTest.TestID
 	}
+	client.invalidateTest(testID)
 
-	d.Set("test_id", fmt.Sprintf("%d", response.TestID))
+	d.Set("test_id", fmt.Sprintf("%d", testID))
 	d.Set("status", response.Status)
 	d.Set("uptime", fmt.Sprintf("%.3f", response.Uptime))
-	d.SetId(fmt.Sprintf("%d", response.TestID))
+	d.Set("client_token", token)
+	d.SetId(fmt.Sprintf("%d", testID))
+
+	newTest.TestID = testID
+	if err := readTestAfterWrite(d, client, newTest, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
 
-	return ReadTest(d, meta)
+	return waitForTestStatus(d, client, nil)
 }
 
 func UpdateTest(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
+	if err := checkAPISupport(client, d, v1UnsupportedTestAttributes); err != nil {
+		return err
+	}
 
 	params := getStatusCakeTestInput(d)
 
+	// The check waited for must be newer than the last one before the update
+	var previous *statuscake.Test
+	if d.Get("wait_for_status").(string) != "" {
+		var err error
+		if previous, err = client.Tests().Detail(params.TestID); err != nil {
+			return fmt.Errorf("Error getting StatusCake Test details for %s: %s", d.Id(), err)
+		}
+	}
+
 	log.Printf("[DEBUG] StatusCake Test Update for %s", d.Id())
 	_, err := client.Tests().Update(params)
+	client.invalidateTest(params.TestID)
+	if err != nil {
+		return fmt.Errorf("Error Updating StatusCake Test: %w", describeAPIError(err, testAPIFields))
+	}
+	if err := readTestAfterWrite(d, client, params, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return err
+	}
+	return waitForTestStatus(d, client, previous)
+}
+
+// readTestAfterWrite waits for the API to reflect the test just written and
+// then updates the state from it.
+func readTestAfterWrite(d *schema.ResourceData, client *StatusCakeClient, written *statuscake.Test, timeout time.Duration) error {
+	test, err := waitForTest(client, written, timeout)
+	if err != nil {
+		return err
+	}
+
+	return setTestState(d, test)
+}
+
+// waitForTest polls a test until the API returns the values just written.
+func waitForTest(client *StatusCakeClient, written *statuscake.Test, timeout time.Duration) (*statuscake.Test, error) {
+	var test *statuscake.Test
+	err := retryRead(client, timeout, fmt.Sprintf("StatusCake Test %d", written.TestID), func() (string, error) {
+		var err error
+		test, err = client.Tests().Detail(written.TestID)
+		if err != nil {
+			return "", err
+		}
+		return staleTestField(written, test), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error waiting for StatusCake Test %d: %s", written.TestID, err)
+	}
+
+	return test, nil
+}
+
+// waitForTestStatus polls a test until its first check after a write has run
+// and fails unless the check reported wait_for_status. previous is the test as
+// it was before an update, nil after a create.
+func waitForTestStatus(d *schema.ResourceData, client *StatusCakeClient, previous *statuscake.Test) error {
+	want := d.Get("wait_for_status").(string)
+	if want == "" {
+		return nil
+	}
+
+	testId, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return fmt.Errorf("Error Updating StatusCake Test: %s", err.Error())
+		return err
 	}
+	timeout, err := time.ParseDuration(d.Get("wait_timeout").(string))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Waiting up to %s for StatusCake Test %d to be checked", timeout, testId)
+	var last *statuscake.Test
+	var processed bool
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		if last != nil {
+			countRetry(client)
+		}
+		test, err := client.Tests().Detail(testId)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		last = test
+
+		if test.Processing {
+			processed = true
+		}
+		if !checkedSince(test, previous, processed) {
+			return resource.RetryableError(fmt.Errorf("StatusCake Test %d has not been checked yet", testId))
+		}
+		return nil
+	})
+	if err != nil {
+		if last != nil {
+			return fmt.Errorf("Error waiting for StatusCake Test %d to be checked, last status %q: %s", testId, last.Status, err)
+		}
+		return fmt.Errorf("Error waiting for StatusCake Test %d to be checked: %s", testId, err)
+	}
+
+	if !strings.EqualFold(last.Status, want) {
+		return fmt.Errorf("StatusCake Test %d is %s after its first check (status code %d, last tested %s), expected %s",
+			testId, last.Status, last.StatusCode, last.LastTested, want)
+	}
+	d.Set("status", last.Status)
+
 	return nil
 }
 
+// checkedSince reports whether test holds the result of a check run after
+// previous was read. Right after an update the API can still return the
+// previous check as if nothing were pending, so a status only counts once the
+// test has been seen processing or its last tested time has moved on. A test
+// just created has no previous read and counts once it has been tested.
+func checkedSince(test, previous *statuscake.Test, processed bool) bool {
+	if test.Processing {
+		return false
+	}
+	if processed {
+		return true
+	}
+	if previous == nil {
+		return test.LastTested != ""
+	}
+
+	return test.LastTested != previous.LastTested
+}
+
 func DeleteTest(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
 
 	testId, parseErr := strconv.Atoi(d.Id())
 	if parseErr != nil {
 		return parseErr
 	}
+
+	if mode := d.Get("on_destroy").(string); mode != onDestroyDelete {
+		return pauseTest(d, client, mode == onDestroyPauseAndTag)
+	}
+
 	log.Printf("[DEBUG] Deleting StatusCake Test: %s", d.Id())
 	err := client.Tests().Delete(testId)
+	client.invalidateTest(testId)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return retryRead(client, d.Timeout(schema.TimeoutDelete), fmt.Sprintf("StatusCake Test %d", testId), func() (string, error) {
+		_, err := client.Tests().Detail(testId)
+		if isNotFound(err) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return "the test still exists", nil
+	})
+}
+
+// clientToken returns the client token of a test created with
+// adopt_existing. It is derived from the attributes that identify the test,
+// so a retried create finds the test an earlier attempt created.
+func clientToken(test *statuscake.Test) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{test.WebsiteName, test.WebsiteURL, test.TestType}, "\n")))
+
+	return clientTokenTagPrefix + hex.EncodeToString(sum[:8])
+}
+
+// findExistingTest returns the test an adopt_existing create takes over: the
+// test tagged with token, or else the only test with the same website name
+// and URL. Tests tagged with another client token belong to another resource
+// and are left alone, and more than one match is an error rather than a
+// guess.
+func findExistingTest(client *StatusCakeClient, test *statuscake.Test, token string) (*statuscake.Test, error) {
+	tests, err := client.Tests().AllWithFilter(url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*statuscake.Test
+	for _, t := range tests {
+		_, owner := splitClientToken(t.TestTags)
+		if owner == token {
+			return t, nil
+		}
+		if owner != "" || t.WebsiteName != test.WebsiteName || t.WebsiteURL != test.WebsiteURL {
+			continue
+		}
+		matches = append(matches, t)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+
+	ids := make([]string, len(matches))
+	for i, t := range matches {
+		ids[i] = strconv.Itoa(t.TestID)
+	}
+	return nil, fmt.Errorf("%d tests are named %q for %s (%s): import the one to manage instead",
+		len(matches), test.WebsiteName, test.WebsiteURL, strings.Join(ids, ", "))
+}
+
+// findTestByClientToken returns the test tagged with token, if any.
+func findTestByClientToken(client *StatusCakeClient, token string) (*statuscake.Test, error) {
+	tests, err := client.Tests().AllWithFilter(url.Values{"tags": {token}})
+	if err != nil || len(tests) == 0 {
+		return nil, err
+	}
+
+	return tests[0], nil
+}
+
+// splitClientToken separates the client token tag from the other tags of a
+// test.
+func splitClientToken(tags []string) ([]string, string) {
+	var token string
+	rest := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, clientTokenTagPrefix) {
+			token = tag
+			continue
+		}
+		rest = append(rest, tag)
+	}
+
+	return rest, token
+}
+
+// pauseTest keeps a destroyed test, and its uptime history, in StatusCake by
+// pausing it instead of deleting it.
+func pauseTest(d *schema.ResourceData, client *StatusCakeClient, tag bool) error {
+	params := getStatusCakeTestInput(d)
+	params.Paused = true
+	if tag {
+		params.TestTags = mergeTags(params.TestTags, []string{decommissionedTag})
+	}
+
+	log.Printf("[DEBUG] Pausing StatusCake Test %s instead of deleting it", d.Id())
+	_, err := client.Tests().Update(params)
+	client.invalidateTest(params.TestID)
+	if err != nil {
+		return fmt.Errorf("Error Pausing StatusCake Test %s: %w", d.Id(), describeAPIError(err, testAPIFields))
+	}
+
+	_, err = waitForTest(client, params, d.Timeout(schema.TimeoutDelete))
+	return err
 }
 
 func ReadTest(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*statuscake.Client)
+	client := meta.(*StatusCakeClient)
 
 	testId, parseErr := strconv.Atoi(d.Id())
 	if parseErr != nil {
 		return parseErr
 	}
-	testResp, err := client.Tests().Detail(testId)
+	if listed, ok, err := client.listedTest(testId); err != nil {
+		log.Printf("[WARN] Error listing StatusCake Tests, reading Test %d on its own: %s", testId, err)
+	} else if ok {
+		if missing := missingTestKey(listed.Keys); missing != "" {
+			log.Printf("[DEBUG] The test list has no %s for StatusCake Test %d, reading it on its own", missing, testId)
+		} else {
+			log.Printf("[DEBUG] Reading StatusCake Test %d from the test list", testId)
+			return setTestFields(d, listed.Test, listed.Keys)
+		}
+	}
+
+	var testResp *statuscake.Test
+	var gone bool
+	err := retryRead(client, d.Timeout(schema.TimeoutRead), fmt.Sprintf("StatusCake Test %d", testId), func() (string, error) {
+		var err error
+		testResp, err = client.Tests().Detail(testId)
+		gone = isNotFound(err)
+		if gone {
+			return "", nil
+		}
+		return "", err
+	})
 	if err != nil {
 		return fmt.Errorf("Error Getting StatusCake Test Details for %s: Error: %s", d.Id(), err)
 	}
+	if gone {
+		log.Printf("[WARN] StatusCake Test %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return setTestState(d, testResp)
+}
+
+// testStateKeys are the JSON keys of the fields setTestFields stores. A
+// listed test is only read from the list when it has all of them, so that
+// changes made outside Terraform always show up. WebsiteHost is left out, as
+// the API returns "" for it anyway.
+var testStateKeys = []string{
+	"CheckRate", "Timeout", "Confirmation", "TriggerRate", "ContactGroup", "NodeLocations",
+	"WebsiteName", "WebsiteURL", "TestType", "Paused", "Port", "CustomHeader", "Status", "Uptime",
+	"LogoImage", "FindString", "DoNotFind", "StatusCodes", "UseJar", "PostRaw", "FinalEndpoint",
+	"EnableSSLAlert", "FollowRedirect", "TestTags",
+}
+
+// missingTestKey returns the first of testStateKeys not in keys, or "" when
+// keys has all of them.
+func missingTestKey(keys map[string]bool) string {
+	for _, key := range testStateKeys {
+		if !keys[key] {
+			return key
+		}
+	}
+	return ""
+}
+
+func setTestState(d *schema.ResourceData, testResp *statuscake.Test) error {
+	return setTestFields(d, testResp, nil)
+}
 
-	if v, ok := d.GetOk("contact_group"); ok {
+// setTestFields sets the state from the fields of testResp whose JSON key is
+// in keys, or from all of them when keys is nil.
+func setTestFields(d *schema.ResourceData, testResp *statuscake.Test, keys map[string]bool) error {
+	has := func(key string) bool {
+		return keys == nil || keys[key]
+	}
+
+	defaultables := make(map[string]interface{})
+	for _, f := range []struct {
+		key, attr string
+		value     interface{}
+	}{
+		{"CheckRate", "check_rate", testResp.CheckRate},
+		{"Timeout", "timeout", testResp.Timeout},
+		{"Confirmation", "confirmations", testResp.Confirmation},
+		{"TriggerRate", "trigger_rate", testResp.TriggerRate},
+	} {
+		if has(f.key) {
+			defaultables[f.attr] = f.value
+		}
+	}
+	if _, ok := appliedDefault(d, "contact_group"); ok {
+		if has("ContactGroup") {
+			defaultables["contact_group"] = testResp.ContactGroup
+		}
+	} else if v, ok := d.GetOk("contact_group"); ok {
 		d.Set("contact_group", v)
 	} else if v, ok := d.GetOk("contact_id"); ok {
 		d.Set("contact_id", v)
 	}
-	d.Set("website_name", testResp.WebsiteName)
-	d.Set("website_url", testResp.WebsiteURL)
-	d.Set("check_rate", testResp.CheckRate)
-	d.Set("test_type", testResp.TestType)
-	d.Set("paused", testResp.Paused)
-	d.Set("timeout", testResp.Timeout)
-	d.Set("confirmations", testResp.Confirmation)
-	d.Set("port", testResp.Port)
-	d.Set("trigger_rate", testResp.TriggerRate)
-	d.Set("custom_header", testResp.CustomHeader)
-	d.Set("status", testResp.Status)
-	d.Set("uptime", testResp.Uptime)
-	if err := d.Set("node_locations", considerEmptyStringAsEmptyArray(testResp.NodeLocations)); err != nil {
-		return fmt.Errorf("[WARN] Error setting node locations: %s", err)
-	}
-	d.Set("logo_image", testResp.LogoImage)
+	if has("NodeLocations") {
+		nodeLocations := considerEmptyStringAsEmptyArray(testResp.NodeLocations)
+		if getLocationSelector(d) != nil {
+			if err := d.Set("resolved_node_locations", nodeLocations); err != nil {
+				return fmt.Errorf("[WARN] Error setting node locations: %s", err)
+			}
+		} else {
+			defaultables["node_locations"] = nodeLocations
+		}
+	}
+	if err := setTestDefaultables(d, defaultables); err != nil {
+		return fmt.Errorf("[WARN] %s", err)
+	}
+
+	for _, f := range []struct {
+		key, attr string
+		value     interface{}
+	}{
+		{"WebsiteName", "website_name", testResp.WebsiteName},
+		{"WebsiteURL", "website_url", testResp.WebsiteURL},
+		{"TestType", "test_type", testResp.TestType},
+		{"Paused", "paused", testResp.Paused},
+		{"Port", "port", testResp.Port},
+		{"CustomHeader", "custom_header", testResp.CustomHeader},
+		{"Status", "status", testResp.Status},
+		{"Uptime", "uptime", testResp.Uptime},
+		{"LogoImage", "logo_image", testResp.LogoImage},
+		{"FindString", "find_string", testResp.FindString},
+		{"DoNotFind", "do_not_find", testResp.DoNotFind},
+		{"StatusCodes", "status_codes", testResp.StatusCodes},
+		{"UseJar", "use_jar", testResp.UseJar},
+		{"PostRaw", "post_raw", testResp.PostRaw},
+		{"FinalEndpoint", "final_endpoint", testResp.FinalEndpoint},
+		{"EnableSSLAlert", "enable_ssl_alert", testResp.EnableSSLAlert},
+		{"FollowRedirect", "follow_redirect", testResp.FollowRedirect},
+	} {
+		if has(f.key) {
+			d.Set(f.attr, f.value)
+		}
+	}
 	// Even after WebsiteHost is set, the API returns ""
 	// API docs aren't clear on usage will only override state if we get a non-empty value back
 	if testResp.WebsiteHost != "" {
 		d.Set("website_host", testResp.WebsiteHost)
 	}
-	d.Set("find_string", testResp.FindString)
-	d.Set("do_not_find", testResp.DoNotFind)
-	d.Set("status_codes", testResp.StatusCodes)
-	d.Set("use_jar", testResp.UseJar)
-	d.Set("post_raw", testResp.PostRaw)
-	d.Set("final_endpoint", testResp.FinalEndpoint)
-	d.Set("enable_ssl_alert", testResp.EnableSSLAlert)
-	d.Set("follow_redirect", testResp.FollowRedirect)
+	if has("TestTags") {
+		tags, token := splitClientToken(testResp.TestTags)
+		if err := d.Set("tags_all", tags); err != nil {
+			return fmt.Errorf("[WARN] Error setting tags: %s", err)
+		}
+		d.Set("client_token", token)
+	}
 
 	return nil
 }
@@ -384,11 +1021,11 @@ func getStatusCakeTestInput(d *schema.ResourceData) *statuscake.Test {
 	if v, ok := d.GetOk("website_url"); ok {
 		test.WebsiteURL = v.(string)
 	}
-	if v, ok := d.GetOk("check_rate"); ok {
-		test.CheckRate = v.(int)
+	if v := testInt(d, "check_rate"); v != 0 {
+		test.CheckRate = v
 	}
-	if v, ok := d.GetOk("contact_group"); ok {
-		test.ContactGroup = castSetToSliceStrings(v.(*schema.Set).List())
+	if v := testStrings(d, "contact_group"); len(v) > 0 {
+		test.ContactGroup = v
 	} else if v, ok := d.GetOk("contact_id"); ok {
 		test.ContactID = v.(int)
 	}
@@ -398,17 +1035,17 @@ func getStatusCakeTestInput(d *schema.ResourceData) *statuscake.Test {
 	if v, ok := d.GetOk("paused"); ok {
 		test.Paused = v.(bool)
 	}
-	if v, ok := d.GetOk("timeout"); ok {
-		test.Timeout = v.(int)
+	if v := testInt(d, "timeout"); v != 0 {
+		test.Timeout = v
 	}
-	if v, ok := d.GetOk("confirmations"); ok {
-		test.Confirmation = v.(int)
+	if v := testInt(d, "confirmations"); v != 0 {
+		test.Confirmation = v
 	}
 	if v, ok := d.GetOk("port"); ok {
 		test.Port = v.(int)
 	}
-	if v, ok := d.GetOk("trigger_rate"); ok {
-		test.TriggerRate = v.(int)
+	if v := testInt(d, "trigger_rate"); v != 0 {
+		test.TriggerRate = v
 	}
 	if v, ok := d.GetOk("custom_header"); ok {
 		test.CustomHeader = v.(string)
@@ -416,8 +1053,8 @@ func getStatusCakeTestInput(d *schema.ResourceData) *statuscake.Test {
 	if v, ok := d.GetOk("user_agent"); ok {
 		test.UserAgent = v.(string)
 	}
-	if v, ok := d.GetOk("node_locations"); ok {
-		test.NodeLocations = castSetToSliceStrings(v.(*schema.Set).List())
+	if v := getNodeLocations(d); len(v) > 0 {
+		test.NodeLocations = v
 	}
 	if v, ok := d.GetOk("ping_url"); ok {
 		test.PingURL = v.(string)
@@ -452,9 +1089,12 @@ func getStatusCakeTestInput(d *schema.ResourceData) *statuscake.Test {
 	if v, ok := d.GetOk("real_browser"); ok {
 		test.RealBrowser = v.(int)
 	}
-	if v, ok := d.GetOk("test_tags"); ok {
+	if v, ok := d.GetOk("tags_all"); ok {
 		test.TestTags = castSetToSliceStrings(v.(*schema.Set).List())
 	}
+	if v, ok := d.GetOk("client_token"); ok {
+		test.TestTags = append(test.TestTags, v.(string))
+	}
 	if v, ok := d.GetOk("status_codes"); ok {
 		test.StatusCodes = v.(string)
 	}
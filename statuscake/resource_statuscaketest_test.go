@@ -3,12 +3,16 @@ package statuscake
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
-	"github.com/DreamItGetIT/statuscake"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
 )
 
 func TestAccStatusCake_basic(t *testing.T) {
@@ -68,6 +72,145 @@ func TestAccStatusCake_tcp(t *testing.T) {
 	})
 }
 
+func TestAccStatusCake_unknownReferences(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTestConfig_unknownReferences,
+				ExpectError: regexp.MustCompile(`contact_group: contact group "0" does not exist(.|\n)*node_locations: "NOTANODE" is not a StatusCake node location`),
+			},
+		},
+	})
+}
+
+func TestAccStatusCake_locationSelector(t *testing.T) {
+	var test statuscake.Test
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccTestCheckDestroy(&test),
+		Steps: []resource.TestStep{
+			{
+				Config: interpolateTerraformTemplate(testAccTestConfig_locationSelector),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTestCheckExists("statuscake_test.google", &test),
+					resource.TestCheckResourceAttr("statuscake_test.google", "location_selector.0.regions.#", "1"),
+					resource.TestMatchResourceAttr("statuscake_test.google", "resolved_node_locations.#", regexp.MustCompile(`^[1-9][0-9]*$`)),
+					resource.TestCheckResourceAttr("statuscake_test.google", "node_locations.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStatusCake_defaultTags(t *testing.T) {
+	var test statuscake.Test
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccTestCheckDestroy(&test),
+		Steps: []resource.TestStep{
+			{
+				Config: interpolateTerraformTemplate(testAccTestConfig_defaultTags),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTestCheckExists("statuscake_test.google", &test),
+					resource.TestCheckResourceAttr("statuscake_test.google", "test_tags.#", "1"),
+					resource.TestCheckResourceAttr("statuscake_test.google", "tags_all.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStatusCake_pauseOnDestroy(t *testing.T) {
+	var test statuscake.Test
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccTestCheckPaused(&test),
+		Steps: []resource.TestStep{
+			{
+				Config: interpolateTerraformTemplate(testAccTestConfig_pauseOnDestroy),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTestCheckExists("statuscake_test.google", &test),
+					resource.TestCheckResourceAttr("statuscake_test.google", "on_destroy", "pause_and_tag"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStatusCake_adoptExisting(t *testing.T) {
+	var test statuscake.Test
+	var existingID int
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccTestCheckDestroy(&test),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					client, err := statuscake.New(statuscake.Auth{
+						Username: os.Getenv("STATUSCAKE_USERNAME"),
+						Apikey:   os.Getenv("STATUSCAKE_APIKEY"),
+					})
+					if err != nil {
+						t.Fatalf("err: %s", err)
+					}
+					existing, err := client.Tests().Update(&statuscake.Test{
+						WebsiteName: "adopt.example.com",
+						WebsiteURL:  "https://adopt.example.com",
+						TestType:    "HTTP",
+						CheckRate:   300,
+					})
+					if err != nil {
+						t.Fatalf("error creating the test to adopt: %s", err)
+					}
+					existingID = existing.TestID
+				},
+				Config: interpolateTerraformTemplate(testAccTestConfig_adoptExisting),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTestCheckExists("statuscake_test.adopted", &test),
+					func(s *terraform.State) error {
+						if test.TestID != existingID {
+							return fmt.Errorf("expected test %d to be adopted, got %d", existingID, test.TestID)
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr("statuscake_test.adopted", "check_rate", "600"),
+					resource.TestMatchResourceAttr("statuscake_test.adopted", "client_token", regexp.MustCompile("^"+clientTokenTagPrefix)),
+					resource.TestCheckResourceAttr("statuscake_test.adopted", "tags_all.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStatusCake_waitForStatus(t *testing.T) {
+	var test statuscake.Test
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccTestCheckDestroy(&test),
+		Steps: []resource.TestStep{
+			{
+				Config: interpolateTerraformTemplate(testAccTestConfig_waitForStatus),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTestCheckExists("statuscake_test.google", &test),
+					resource.TestCheckResourceAttr("statuscake_test.google", "status", "Up"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccStatusCake_withUpdate(t *testing.T) {
 	var test statuscake.Test
 
@@ -84,7 +227,7 @@ func TestAccStatusCake_withUpdate(t *testing.T) {
 			},
 
 			{
-				Config: testAccTestConfig_update,
+				Config: interpolateNodeLocations(testAccTestConfig_update),
 				Check: resource.ComposeTestCheckFunc(
 					testAccTestCheckExists("statuscake_test.google", &test),
 					testAccTestCheckAttributes("statuscake_test.google", &test),
@@ -122,6 +265,193 @@ func TestAccStatusCake_withUpdate(t *testing.T) {
 	})
 }
 
+func TestSplitClientToken(t *testing.T) {
+	tags, token := splitClientToken([]string{"api", clientTokenTagPrefix + "20191019", "team:web"})
+	if token != clientTokenTagPrefix+"20191019" {
+		t.Fatalf("expected the client token to be found, got %q", token)
+	}
+	if strings.Join(tags, ",") != "api,team:web" {
+		t.Fatalf("expected the other tags to be kept, got %v", tags)
+	}
+}
+
+func TestCheckedSince(t *testing.T) {
+	previous := &statuscake.Test{Status: "Down", LastTested: "2019-10-19 10:00:00"}
+	cases := []struct {
+		test      statuscake.Test
+		previous  *statuscake.Test
+		processed bool
+		expected  bool
+	}{
+		{statuscake.Test{Processing: true}, nil, true, false},
+		{statuscake.Test{Status: "Up", LastTested: "2019-10-19 10:05:00"}, nil, false, true},
+		{statuscake.Test{Status: "Up"}, nil, true, true},
+		// A new test that has not been checked yet
+		{statuscake.Test{Status: "Up"}, nil, false, false},
+		// The previous check, returned before the update is picked up
+		{statuscake.Test{Status: "Down", LastTested: "2019-10-19 10:00:00"}, previous, false, false},
+		{statuscake.Test{Status: "Down", LastTested: "2019-10-19 10:00:00"}, previous, true, true},
+		{statuscake.Test{Status: "Up", LastTested: "2019-10-19 10:05:00"}, previous, false, true},
+	}
+	for i, c := range cases {
+		if got := checkedSince(&c.test, c.previous, c.processed); got != c.expected {
+			t.Errorf("%d: expected %t, got %t", i, c.expected, got)
+		}
+	}
+}
+
+func TestClientToken(t *testing.T) {
+	test := &statuscake.Test{WebsiteName: "adopt.example.com", WebsiteURL: "https://adopt.example.com", TestType: "HTTP"}
+	token := clientToken(test)
+	if !strings.HasPrefix(token, clientTokenTagPrefix) {
+		t.Fatalf("expected the client token to start with %q, got %q", clientTokenTagPrefix, token)
+	}
+	if again := clientToken(test); again != token {
+		t.Fatalf("expected the same client token for the same test, got %q and %q", token, again)
+	}
+	if other := clientToken(&statuscake.Test{WebsiteName: test.WebsiteName, WebsiteURL: test.WebsiteURL, TestType: "TCP"}); other == token {
+		t.Fatalf("expected a different client token for another test type, got %q", other)
+	}
+}
+
+func TestCreateTest_adoptExisting(t *testing.T) {
+	srv, client := testServerClient(t)
+	defer srv.Close()
+
+	config := map[string]interface{}{
+		"website_name":   "adopt.example.com",
+		"website_url":    "https://adopt.example.com",
+		"test_type":      "HTTP",
+		"check_rate":     300,
+		"adopt_existing": true,
+	}
+	first := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, config)
+	if err := CreateTest(first, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// A retried create finds the test by its client token
+	retried := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, config)
+	if err := CreateTest(retried, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if retried.Id() != first.Id() {
+		t.Fatalf("expected test %s to be adopted, got %s", first.Id(), retried.Id())
+	}
+
+	// A validation error is not taken for a create that went through
+	config["check_rate"] = 99999
+	invalid := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, config)
+	if err := CreateTest(invalid, client); err == nil || !strings.Contains(err.Error(), "check_rate must be between") {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	config["check_rate"] = 300
+
+	// Another test type does not take over the first test
+	config["test_type"] = "TCP"
+	other := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, config)
+	if err := CreateTest(other, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if other.Id() == first.Id() {
+		t.Fatalf("expected a new test, got %s", other.Id())
+	}
+
+	// Untagged duplicates are ambiguous
+	for i := 0; i < 2; i++ {
+		if _, err := client.Tests().Update(&statuscake.Test{WebsiteName: "dup.example.com", WebsiteURL: "https://dup.example.com", TestType: "HTTP"}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	config["website_name"], config["website_url"] = "dup.example.com", "https://dup.example.com"
+	dup := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, config)
+	if err := CreateTest(dup, client); err == nil || !strings.Contains(err.Error(), "2 tests are named") {
+		t.Fatalf("expected an error about the duplicate tests, got %v", err)
+	}
+}
+
+func TestReadTest_fromList(t *testing.T) {
+	srv, client := testServerClient(t)
+	defer srv.Close()
+
+	var tests []*schema.ResourceData
+	for _, name := range []string{"api", "web", "db"} {
+		test := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, map[string]interface{}{
+			"website_name": name,
+			"website_url":  "https://" + name + ".example.com",
+			"test_type":    "HTTP",
+			"timeout":      20,
+			"status_codes": "200,201",
+		})
+		if err := CreateTest(test, client); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		tests = append(tests, test)
+	}
+
+	// The timeout is changed outside Terraform, and the list leaves it out
+	id, _ := strconv.Atoi(tests[0].Id())
+	changed, err := client.Tests().Detail(id)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	changed.Timeout = 30
+	if _, err := client.Tests().Update(changed); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// A refresh in a later run starts with an empty test list
+	client.providerState = &providerState{deletes: &deleteLedger{}}
+	details := srv.Requests("GET /Tests/Details")
+
+	for i, test := range tests {
+		if err := ReadTest(test, client); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		timeout := 20
+		if i == 0 {
+			timeout = 30
+		}
+		if test.Get("timeout").(int) != timeout || test.Get("status_codes").(string) != "200,201" || test.Get("status").(string) != "Up" {
+			t.Errorf("unexpected state after reading %s: %#v", test.Id(), test.State().Attributes)
+		}
+	}
+	if n := srv.Requests("GET /Tests/Details") - details; n != len(tests) {
+		t.Errorf("expected the tests missing from the list to be read with Details, got %d detail requests", n)
+	}
+	if n := srv.Requests("GET /Tests"); n != 1 {
+		t.Errorf("expected one test list request, got %d", n)
+	}
+}
+
+func TestMissingTestKey(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, key := range testStateKeys {
+		keys[key] = true
+	}
+	if missing := missingTestKey(keys); missing != "" {
+		t.Fatalf("expected no missing key, got %q", missing)
+	}
+
+	delete(keys, "Timeout")
+	if missing := missingTestKey(keys); missing != "Timeout" {
+		t.Fatalf("expected Timeout to be missing, got %q", missing)
+	}
+}
+
+// testServerClient returns a provider client talking to a stand-in server.
+func testServerClient(t *testing.T) (*statuscaketest.Server, *StatusCakeClient) {
+	srv := statuscaketest.NewServer()
+	c, err := statuscake.New(statuscake.Auth{Username: statuscaketest.Username, Apikey: statuscaketest.Apikey},
+		statuscake.WithBaseURL(srv.URL))
+	if err != nil {
+		srv.Close()
+		t.Fatalf("err: %s", err)
+	}
+
+	return srv, &StatusCakeClient{Client: c, providerState: &providerState{deletes: &deleteLedger{}}}
+}
+
 func testAccTestCheckExists(rn string, test *statuscake.Test) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -133,7 +463,7 @@ func testAccTestCheckExists(rn string, test *statuscake.Test) resource.TestCheck
 			return fmt.Errorf("TestID not set")
 		}
 
-		client := testAccProvider.Meta().(*statuscake.Client)
+		client := testAccProvider.Meta().(*StatusCakeClient)
 		testId, parseErr := strconv.Atoi(rs.Primary.ID)
 		if parseErr != nil {
 			return fmt.Errorf("error in statuscake test CheckExists: %s", parseErr)
@@ -229,7 +559,7 @@ func testAccTestCheckAttributes(rn string, test *statuscake.Test) resource.TestC
 
 func testAccTestCheckDestroy(test *statuscake.Test) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*statuscake.Client)
+		client := testAccProvider.Meta().(*StatusCakeClient)
 		err := client.Tests().Delete(test.TestID)
 		if err == nil {
 			return fmt.Errorf("test still exists")
@@ -239,6 +569,30 @@ func testAccTestCheckDestroy(test *statuscake.Test) resource.TestCheckFunc {
 	}
 }
 
+// testAccTestCheckPaused checks a test destroyed with on_destroy set to
+// pause_and_tag is kept paused and tagged, then deletes it.
+func testAccTestCheckPaused(test *statuscake.Test) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*StatusCakeClient)
+		gotTest, err := client.Tests().Detail(test.TestID)
+		if err != nil {
+			return fmt.Errorf("expected the test to be kept: %s", err)
+		}
+		defer client.Tests().Delete(test.TestID)
+
+		if !gotTest.Paused {
+			return fmt.Errorf("expected the test to be paused")
+		}
+		for _, tag := range gotTest.TestTags {
+			if tag == decommissionedTag {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("expected the test to be tagged %q, got %v", decommissionedTag, gotTest.TestTags)
+	}
+}
+
 func interpolateTerraformTemplate(template string) string {
 	testContactGroupId := "43402"
 
@@ -249,6 +603,23 @@ func interpolateTerraformTemplate(template string) string {
 	return fmt.Sprintf(template, testContactGroupId)
 }
 
+// interpolateNodeLocations fills in three node location server codes, which
+// must exist since plans now reject unknown locations.
+func interpolateNodeLocations(template string) string {
+	nodeLocations := "UK1,UK2,UK3"
+
+	if v := os.Getenv("STATUSCAKE_TEST_NODE_LOCATIONS"); v != "" {
+		nodeLocations = v
+	}
+
+	quoted := strings.Split(nodeLocations, ",")
+	for i, code := range quoted {
+		quoted[i] = strconv.Quote(strings.TrimSpace(code))
+	}
+
+	return fmt.Sprintf(template, strings.Join(quoted, ", "))
+}
+
 const testAccTestConfig_basic = `
 resource "statuscake_test" "google" {
 	website_name = "google.com"
@@ -283,7 +654,7 @@ resource "statuscake_test" "google" {
 	trigger_rate = 20
 	custom_header = "{ \"Content-Type\": \"application/x-www-form-urlencoded\" }"
 	user_agent = "string9988"
-	node_locations = [ "string16045", "string19741", "string12122" ]
+	node_locations = [ %s ]
 	ping_url = "string8410"
 	basic_user = "string27052"
 	basic_pass = "string5659"
@@ -305,6 +676,76 @@ resource "statuscake_test" "google" {
 }
 `
 
+const testAccTestConfig_unknownReferences = `
+resource "statuscake_test" "google" {
+	website_name = "google.com"
+	website_url = "www.google.com"
+	test_type = "HTTP"
+	contact_group = ["0"]
+	node_locations = ["NOTANODE"]
+}
+`
+
+const testAccTestConfig_locationSelector = `
+resource "statuscake_test" "google" {
+	website_name = "google.com"
+	website_url = "www.google.com"
+	test_type = "HTTP"
+	contact_group = ["%s"]
+
+	location_selector {
+		regions = ["europe"]
+		min_count = 1
+	}
+}
+`
+
+const testAccTestConfig_defaultTags = `
+provider "statuscake" {
+	default_tags = ["team:web", "env:test"]
+}
+
+resource "statuscake_test" "google" {
+	website_name = "google.com"
+	website_url = "www.google.com"
+	test_type = "HTTP"
+	contact_group = ["%s"]
+	test_tags = ["env:test", "service:search"]
+}
+`
+
+const testAccTestConfig_pauseOnDestroy = `
+resource "statuscake_test" "google" {
+	website_name = "google.com"
+	website_url = "www.google.com"
+	test_type = "HTTP"
+	contact_group = ["%s"]
+	on_destroy = "pause_and_tag"
+}
+`
+
+const testAccTestConfig_adoptExisting = `
+resource "statuscake_test" "adopted" {
+	website_name = "adopt.example.com"
+	website_url = "https://adopt.example.com"
+	test_type = "HTTP"
+	check_rate = 600
+	contact_group = ["%s"]
+	adopt_existing = true
+}
+`
+
+const testAccTestConfig_waitForStatus = `
+resource "statuscake_test" "google" {
+	website_name = "google.com"
+	website_url = "https://www.google.com"
+	test_type = "HTTP"
+	contact_group = ["%s"]
+	wait_for_status = "Up"
+	wait_timeout = "10m"
+}
+`
+
 const testAccTestConfig_tcp = `
 resource "statuscake_test" "google" {
 	website_name = "google.com"
@@ -0,0 +1,59 @@
+package statuscake
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// mergeTags returns the sorted union of the provider default tags and the
+// tags configured on a resource.
+func mergeTags(defaultTags, tags []string) []string {
+	seen := make(map[string]bool, len(defaultTags)+len(tags))
+	merged := make([]string, 0, len(defaultTags)+len(tags))
+
+	for _, list := range [][]string{defaultTags, tags} {
+		for _, tag := range list {
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	sort.Strings(merged)
+
+	return merged
+}
+
+// customizeTagsAll returns a CustomizeDiffFunc that keeps the computed
+// tagsAllKey in line with the provider default_tags merged into tagsKey.
+// Default tags therefore only ever show up as a change to the computed set.
+func customizeTagsAll(tagsKey, tagsAllKey string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		client := meta.(*StatusCakeClient)
+
+		if !d.NewValueKnown(tagsKey) {
+			return d.SetNewComputed(tagsAllKey)
+		}
+
+		tags := castSetToSliceStrings(d.Get(tagsKey).(*schema.Set).List())
+		merged := mergeTags(client.defaultTags, tags)
+
+		current := d.Get(tagsAllKey).(*schema.Set)
+		if d.Id() != "" && current.Equal(schema.NewSet(schema.HashString, stringsToInterfaces(merged))) {
+			return nil
+		}
+
+		return d.SetNew(tagsAllKey, merged)
+	}
+}
+
+func stringsToInterfaces(in []string) []interface{} {
+	out := make([]interface{}, len(in))
+	for i, v := range in {
+		out[i] = v
+	}
+
+	return out
+}
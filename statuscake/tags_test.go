@@ -0,0 +1,27 @@
+package statuscake
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeTags(t *testing.T) {
+	cases := []struct {
+		defaultTags []string
+		tags        []string
+		expected    []string
+	}{
+		{nil, nil, []string{}},
+		{[]string{"team:web"}, nil, []string{"team:web"}},
+		{nil, []string{"api"}, []string{"api"}},
+		{[]string{"team:web", "env:prod"}, []string{"api", "env:prod"}, []string{"api", "env:prod", "team:web"}},
+		{[]string{""}, []string{"api"}, []string{"api"}},
+	}
+
+	for _, tc := range cases {
+		merged := mergeTags(tc.defaultTags, tc.tags)
+		if !reflect.DeepEqual(merged, tc.expected) {
+			t.Errorf("mergeTags(%v, %v): expected %v, got %v", tc.defaultTags, tc.tags, tc.expected, merged)
+		}
+	}
+}
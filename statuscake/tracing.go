@@ -0,0 +1,133 @@
+package statuscake
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/otlp"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+	"go.opencensus.io/trace"
+)
+
+// Environment variables configuring tracing, as defined by OpenTelemetry.
+// Tracing is off unless an endpoint is set.
+const (
+	otlpEndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	otlpTracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	otlpHeadersEnv        = "OTEL_EXPORTER_OTLP_HEADERS"
+	serviceNameEnv        = "OTEL_SERVICE_NAME"
+
+	defaultServiceName = "terraform-provider-statuscake"
+)
+
+var (
+	tracingOnce   sync.Once
+	traceExporter *otlp.Exporter
+)
+
+// startTracing registers the span exporter the environment asks for, once
+// per provider process.
+func startTracing() {
+	tracingOnce.Do(func() {
+		endpoint := os.Getenv(otlpTracesEndpointEnv)
+		if endpoint == "" {
+			if base := os.Getenv(otlpEndpointEnv); base != "" {
+				endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+			}
+		}
+		if endpoint == "" {
+			return
+		}
+
+		serviceName := os.Getenv(serviceNameEnv)
+		if serviceName == "" {
+			serviceName = defaultServiceName
+		}
+
+		log.Printf("[INFO] Exporting StatusCake provider traces to %s", endpoint)
+		traceExporter = otlp.NewExporter(endpoint, serviceName, parseOTLPHeaders(os.Getenv(otlpHeadersEnv)))
+		trace.RegisterExporter(traceExporter)
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	})
+}
+
+// parseOTLPHeaders parses the "key=value,key=value" list of headers sent to
+// the collector.
+func parseOTLPHeaders(v string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			continue
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return headers
+}
+
+// traceResource wraps the CRUD functions of a resource or data source in a
+// span. The API requests made by the operation are its children.
+func traceResource(name string, r *schema.Resource) {
+	if r.Create != nil {
+		r.Create = schema.CreateFunc(traceOperation(name, "Create", writeFunc(r.Create)))
+	}
+	if r.Read != nil {
+		r.Read = schema.ReadFunc(traceOperation(name, "Read", writeFunc(r.Read)))
+	}
+	if r.Update != nil {
+		r.Update = schema.UpdateFunc(traceOperation(name, "Update", writeFunc(r.Update)))
+	}
+	if r.Delete != nil {
+		r.Delete = schema.DeleteFunc(traceOperation(name, "Delete", writeFunc(r.Delete)))
+	}
+}
+
+func traceOperation(name, op string, f writeFunc) writeFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		client := meta.(*StatusCakeClient)
+
+		retries := new(int64)
+		ctx := statuscake.WithRetryCounter(client.Context(), retries)
+		ctx, span := trace.StartSpan(ctx, fmt.Sprintf("%s.%s", name, op))
+		span.AddAttributes(trace.StringAttribute("terraform.resource", name))
+
+		err := f(d, client.withContext(ctx))
+
+		span.AddAttributes(
+			trace.StringAttribute("statuscake.id", d.Id()),
+			trace.Int64Attribute("statuscake.retries", atomic.LoadInt64(retries)),
+		)
+		if err != nil {
+			span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+		}
+		span.End()
+		flushTraces()
+
+		return err
+	}
+}
+
+// countRetry records a retried API call on the span of the operation, and on
+// the spans of the requests that follow it.
+func countRetry(client *StatusCakeClient) {
+	statuscake.CountRetry(client.Context())
+}
+
+// flushTraces starts sending the spans ended so far in the background. It
+// runs after every operation, as the provider process can be stopped at any
+// time after that, but never waits for the collector.
+func flushTraces() {
+	startTracing()
+	if traceExporter == nil {
+		return
+	}
+	traceExporter.FlushAsync(func(err error) {
+		log.Printf("[WARN] Error exporting StatusCake provider traces: %s", err)
+	})
+}
@@ -0,0 +1,156 @@
+package statuscake
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscake"
+	"github.com/terraform-providers/terraform-provider-statuscake/internal/statuscaketest"
+	"go.opencensus.io/trace"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (e *recordingExporter) ExportSpan(sd *trace.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, sd)
+}
+
+func TestTraceOperation(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	c, err := statuscake.New(statuscake.Auth{Username: statuscaketest.Username, Apikey: statuscaketest.Apikey},
+		statuscake.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &StatusCakeClient{Client: c, providerState: &providerState{deletes: &deleteLedger{}}}
+
+	exporter := &recordingExporter{}
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	defer trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(1e-4)})
+
+	r := resourceStatusCakeContactGroup()
+	traceResource("statuscake_contact_group", r)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"group_name": "traced",
+		"emails":     []interface{}{"traced@example.com"},
+	})
+	if err := r.Create(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var op *trace.SpanData
+	for _, s := range exporter.spans {
+		if s.Name == "statuscake_contact_group.Create" {
+			op = s
+		}
+	}
+	if op == nil {
+		t.Fatalf("no operation span in %d spans", len(exporter.spans))
+	}
+	if op.Attributes["statuscake.id"] != d.Id() {
+		t.Errorf("expected statuscake.id %s, got %v", d.Id(), op.Attributes["statuscake.id"])
+	}
+	if _, ok := op.Attributes["statuscake.retries"].(int64); !ok {
+		t.Errorf("expected a statuscake.retries count, got %v", op.Attributes["statuscake.retries"])
+	}
+
+	var requests int
+	for _, s := range exporter.spans {
+		if s == op {
+			continue
+		}
+		requests++
+		if s.ParentSpanID != op.SpanID || s.TraceID != op.TraceID {
+			t.Errorf("expected %s to be a child of the operation span", s.Name)
+		}
+		if s.SpanKind != trace.SpanKindClient {
+			t.Errorf("expected %s to be a client span", s.Name)
+		}
+		if s.Name == "StatusCake PUT /ContactGroups/Update" && s.Attributes["http.status_code"] != int64(200) {
+			t.Errorf("expected status code 200 on %s, got %v", s.Name, s.Attributes["http.status_code"])
+		}
+	}
+	if requests == 0 {
+		t.Error("expected spans for the API requests")
+	}
+}
+
+func TestCountRetry(t *testing.T) {
+	srv := statuscaketest.NewServer()
+	defer srv.Close()
+
+	c, err := statuscake.New(statuscake.Auth{Username: statuscaketest.Username, Apikey: statuscaketest.Apikey},
+		statuscake.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := &StatusCakeClient{Client: c, providerState: &providerState{}}
+
+	exporter := &recordingExporter{}
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	defer trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(1e-4)})
+
+	// Counting outside a traced operation is a no-op.
+	countRetry(client)
+
+	traced := traceOperation("statuscake_test", "Read", func(d *schema.ResourceData, meta interface{}) error {
+		client := meta.(*StatusCakeClient)
+		for i := 0; i < 3; i++ {
+			if i > 0 {
+				countRetry(client)
+			}
+			if _, err := client.Tests().Detail(1); err == nil {
+				t.Error("expected test 1 not to exist")
+			}
+		}
+		return nil
+	})
+	d := schema.TestResourceDataRaw(t, resourceStatusCakeTest().Schema, map[string]interface{}{})
+	if err := traced(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var requests []interface{}
+	var op *trace.SpanData
+	for _, s := range exporter.spans {
+		if s.Name == "statuscake_test.Read" {
+			op = s
+		} else {
+			requests = append(requests, s.Attributes["statuscake.retries"])
+		}
+	}
+	if op == nil {
+		t.Fatalf("no operation span in %d spans", len(exporter.spans))
+	}
+	if got := op.Attributes["statuscake.retries"]; got != int64(2) {
+		t.Errorf("expected 2 retries, got %v", got)
+	}
+	if want := []interface{}{int64(0), int64(1), int64(2)}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected the request spans to record %v retries, got %v", want, requests)
+	}
+}
+
+func TestParseOTLPHeaders(t *testing.T) {
+	got := parseOTLPHeaders("Authorization=Bearer a=b, x-team = ops,,invalid")
+	want := map[string]string{"Authorization": "Bearer a=b", "x-team": "ops"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := parseOTLPHeaders(""); len(got) != 0 {
+		t.Errorf("expected no headers, got %v", got)
+	}
+}
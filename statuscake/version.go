@@ -0,0 +1,25 @@
+package statuscake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProviderVersion is the version of the provider, set at build time with
+// -ldflags "-X github.com/terraform-providers/terraform-provider-statuscake/statuscake.ProviderVersion=1.2.3".
+var ProviderVersion = "dev"
+
+// userAgent identifies the provider and the Terraform running it to
+// StatusCake. Terraform 0.11 and older do not report their version.
+func userAgent(terraformVersion, suffix string) string {
+	if terraformVersion == "" {
+		terraformVersion = "0.11+compatible"
+	}
+
+	ua := fmt.Sprintf("terraform-provider-statuscake/%s terraform/%s", ProviderVersion, terraformVersion)
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		ua += " " + suffix
+	}
+
+	return ua
+}
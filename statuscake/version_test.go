@@ -0,0 +1,19 @@
+package statuscake
+
+import "testing"
+
+func TestUserAgent(t *testing.T) {
+	cases := []struct {
+		terraformVersion, suffix, expected string
+	}{
+		{"0.12.6", "", "terraform-provider-statuscake/dev terraform/0.12.6"},
+		{"", "", "terraform-provider-statuscake/dev terraform/0.11+compatible"},
+		{"0.12.6", " deploy-pipeline ", "terraform-provider-statuscake/dev terraform/0.12.6 deploy-pipeline"},
+	}
+
+	for _, tc := range cases {
+		if got := userAgent(tc.terraformVersion, tc.suffix); got != tc.expected {
+			t.Errorf("userAgent(%q, %q): expected %q, got %q", tc.terraformVersion, tc.suffix, tc.expected, got)
+		}
+	}
+}